@@ -0,0 +1,86 @@
+package pears
+
+import "errors"
+
+// AbortCounts holds the running counts of a Group at the time an error is collected.
+type AbortCounts struct {
+	// Launched is the number of ops launched so far.
+	Launched int
+	// Finished is the number of ops that have finished so far, successfully or not.
+	Finished int
+	// Failed is the number of errors collected so far, including the one being
+	// evaluated.
+	Failed int
+}
+
+// AbortPolicy decides whether a Group should abort when a new OpError is collected.
+// If it returns true, the Group's context is cancelled.
+//
+// An AbortPolicy is only called from the Group's error collection routine, so it does
+// not need to be safe for concurrent use within a single Group. Once the Group has
+// aborted, the policy is no longer called.
+type AbortPolicy = func(err OpError, counts AbortCounts) bool
+
+// AbortOnFirstError returns an AbortPolicy that aborts on the first error.
+func AbortOnFirstError() AbortPolicy {
+	return func(err OpError, counts AbortCounts) bool {
+		return true
+	}
+}
+
+// AbortAfterErrors returns an AbortPolicy that aborts once n errors have been
+// collected.
+func AbortAfterErrors(n int) AbortPolicy {
+	return func(err OpError, counts AbortCounts) bool {
+		return counts.Failed >= n
+	}
+}
+
+// AbortOnErrorRatio returns an AbortPolicy that aborts once the ratio of failed ops to
+// finished ops is greater than ratio, a value between 0 and 1. The ratio is not
+// checked until at least minFinished ops have finished.
+func AbortOnErrorRatio(ratio float64, minFinished int) AbortPolicy {
+	return func(err OpError, counts AbortCounts) bool {
+		if counts.Finished < minFinished || counts.Finished == 0 {
+			return false
+		}
+		return float64(counts.Failed)/float64(counts.Finished) > ratio
+	}
+}
+
+// AbortOnMatch returns an AbortPolicy that aborts when match returns true for an
+// error.
+func AbortOnMatch(match func(err error) bool) AbortPolicy {
+	return func(err OpError, counts AbortCounts) bool {
+		return match(err)
+	}
+}
+
+// AbortOnErrorIs returns an AbortPolicy that aborts when an error passes
+// errors.Is for any of targets.
+func AbortOnErrorIs(targets ...error) AbortPolicy {
+	return AbortOnMatch(func(err error) bool {
+		for _, target := range targets {
+			if errors.Is(err, target) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// AbortIgnoringOps returns an AbortPolicy that never aborts because of an error from
+// an op named in opNames. Errors from all other ops are passed to policy.
+func AbortIgnoringOps(policy AbortPolicy, opNames ...string) AbortPolicy {
+	ignored := make(map[string]struct{}, len(opNames))
+	for _, name := range opNames {
+		ignored[name] = struct{}{}
+	}
+
+	return func(err OpError, counts AbortCounts) bool {
+		if _, ok := ignored[err.OpName]; ok {
+			return false
+		}
+		return policy(err, counts)
+	}
+}
@@ -0,0 +1,151 @@
+package pears_test
+
+import (
+	"context"
+	"github.com/peake100/pears-go/pkg/pears"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestAbortPolicies(t *testing.T) {
+	opErr := pears.OpError{OpName: "op", Err: io.EOF}
+
+	testCases := []struct {
+		// The name of the test case.
+		Name string
+		// The policy to test.
+		Policy pears.AbortPolicy
+		// The error to pass to the policy.
+		Err pears.OpError
+		// The counts to pass to the policy.
+		Counts pears.AbortCounts
+		// Whether the policy is expected to abort.
+		Expected bool
+	}{
+		{
+			Name:     "FirstError",
+			Policy:   pears.AbortOnFirstError(),
+			Err:      opErr,
+			Counts:   pears.AbortCounts{Launched: 10, Finished: 1, Failed: 1},
+			Expected: true,
+		},
+		{
+			Name:     "AfterErrors_Below",
+			Policy:   pears.AbortAfterErrors(3),
+			Err:      opErr,
+			Counts:   pears.AbortCounts{Launched: 10, Finished: 5, Failed: 2},
+			Expected: false,
+		},
+		{
+			Name:     "AfterErrors_Reached",
+			Policy:   pears.AbortAfterErrors(3),
+			Err:      opErr,
+			Counts:   pears.AbortCounts{Launched: 10, Finished: 5, Failed: 3},
+			Expected: true,
+		},
+		{
+			Name:     "ErrorRatio_TooFewFinished",
+			Policy:   pears.AbortOnErrorRatio(0.5, 4),
+			Err:      opErr,
+			Counts:   pears.AbortCounts{Launched: 10, Finished: 3, Failed: 3},
+			Expected: false,
+		},
+		{
+			Name:     "ErrorRatio_Below",
+			Policy:   pears.AbortOnErrorRatio(0.5, 4),
+			Err:      opErr,
+			Counts:   pears.AbortCounts{Launched: 10, Finished: 4, Failed: 2},
+			Expected: false,
+		},
+		{
+			Name:     "ErrorRatio_Passed",
+			Policy:   pears.AbortOnErrorRatio(0.5, 4),
+			Err:      opErr,
+			Counts:   pears.AbortCounts{Launched: 10, Finished: 5, Failed: 3},
+			Expected: true,
+		},
+		{
+			Name:     "ErrorIs_Match",
+			Policy:   pears.AbortOnErrorIs(io.ErrClosedPipe, io.EOF),
+			Err:      opErr,
+			Counts:   pears.AbortCounts{Launched: 1, Finished: 1, Failed: 1},
+			Expected: true,
+		},
+		{
+			Name:     "ErrorIs_NoMatch",
+			Policy:   pears.AbortOnErrorIs(io.ErrClosedPipe),
+			Err:      opErr,
+			Counts:   pears.AbortCounts{Launched: 1, Finished: 1, Failed: 1},
+			Expected: false,
+		},
+		{
+			Name:     "IgnoringOps_Ignored",
+			Policy:   pears.AbortIgnoringOps(pears.AbortOnFirstError(), "other", "op"),
+			Err:      opErr,
+			Counts:   pears.AbortCounts{Launched: 1, Finished: 1, Failed: 1},
+			Expected: false,
+		},
+		{
+			Name:     "IgnoringOps_NotIgnored",
+			Policy:   pears.AbortIgnoringOps(pears.AbortOnFirstError(), "other"),
+			Err:      opErr,
+			Counts:   pears.AbortCounts{Launched: 1, Finished: 1, Failed: 1},
+			Expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			result := tc.Policy(tc.Err, tc.Counts)
+			assert.Equal(t, tc.Expected, result, "abort decision")
+		})
+	}
+}
+
+func TestRoutineManager_AbortPolicy_AfterErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	manager := pears.NewGroup(ctx, pears.WithAbortPolicy(pears.AbortAfterErrors(2)))
+
+	firstFailed := make(chan struct{})
+	manager.GoNamed("fail1", func(ctx context.Context) error {
+		defer close(firstFailed)
+		return io.EOF
+	})
+
+	manager.GoNamed("fail2", func(ctx context.Context) error {
+		<-firstFailed
+
+		// Give the collector time to receive the first error, and make sure it did not
+		// abort the group.
+		timer := time.NewTimer(50 * time.Millisecond)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			t.Error("group aborted after first error")
+		case <-timer.C:
+		}
+
+		return io.ErrUnexpectedEOF
+	})
+
+	manager.GoNamed("waiter", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	err := manager.Wait()
+
+	batchErrs := pears.GroupErrors{}
+	if !assert.ErrorAs(err, &batchErrs) {
+		t.FailNow()
+	}
+	assert.Len(batchErrs.Errs, 3, "all ops returned errors")
+	assert.Len(batchErrs.RootCauses(), 2, "2 root causes")
+	assert.ErrorIs(batchErrs.Errs[2], context.Canceled, "waiter aborted")
+}
@@ -0,0 +1,164 @@
+package pears
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// summaryOpNames is the number of op names printed for each bucket by
+// AggregatedErrors.Format.
+const summaryOpNames = 5
+
+// AggregateKey returns the key an error is bucketed under by GroupErrors.Aggregate.
+// For an OpError, it is passed OpError.Err rather than the OpError itself, so errors
+// from different ops can share a bucket.
+//
+// Keys must be comparable, as they are used as map keys. They are printed with fmt's
+// %v verb when an AggregatedErrors is formatted.
+type AggregateKey = func(err error) interface{}
+
+// AggregateByMessage returns an AggregateKey that buckets errors by their Error()
+// text. Keys are strings.
+func AggregateByMessage() AggregateKey {
+	return func(err error) interface{} {
+		return err.Error()
+	}
+}
+
+// AggregateByIs returns an AggregateKey that buckets errors by the first of targets
+// they pass errors.Is for. Each target gets it's own bucket, even if two targets have
+// the same Error() text, and the bucket's key prints as the target's Error() text.
+// Errors that do not match any target are bucketed by their own Error() text, as
+// string keys, which never share a bucket with a target.
+func AggregateByIs(targets ...error) AggregateKey {
+	return func(err error) interface{} {
+		for i, target := range targets {
+			if errors.Is(err, target) {
+				return targetKey{index: i, message: target.Error()}
+			}
+		}
+		return err.Error()
+	}
+}
+
+// targetKey is the key AggregateByIs buckets errors matching a target under. Targets
+// are told apart by their index, as the targets themselves may not be comparable.
+type targetKey struct {
+	// index is the position of the target in the arguments to AggregateByIs.
+	index int
+	// message is the target's Error() text.
+	message string
+}
+
+// String implements fmt.Stringer.
+func (key targetKey) String() string {
+	return key.message
+}
+
+// ErrorBucket is a group of similar errors returned by GroupErrors.Aggregate.
+type ErrorBucket struct {
+	// Key is the key returned by the AggregateKey for every error in the bucket.
+	Key interface{}
+	// Errs are the errors in the bucket, in the order they appear in
+	// GroupErrors.Errs.
+	Errs []error
+	// OpNames holds the OpName of every OpError in Errs, in the same order.
+	OpNames []string
+}
+
+// Count returns the number of errors in the bucket.
+func (bucket ErrorBucket) Count() int {
+	return len(bucket.Errs)
+}
+
+// Aggregate groups Errs into buckets of errors that share the same key. If key is nil,
+// AggregateByMessage is used. Buckets are ordered from the most to the least errors,
+// with ties ordered by the first appearance of their key in Errs.
+//
+// Only errors retained in Errs are bucketed. Nested GroupErrors are bucketed as a
+// single error; use Flatten first to bucket every leaf error.
+func (err GroupErrors) Aggregate(key AggregateKey) []ErrorBucket {
+	if key == nil {
+		key = AggregateByMessage()
+	}
+
+	var buckets []*ErrorBucket
+	byKey := make(map[interface{}]*ErrorBucket)
+
+	for _, thisErr := range err.Errs {
+		keyErr := thisErr
+		opErr, isOpErr := thisErr.(OpError)
+		if isOpErr {
+			keyErr = opErr.Err
+		}
+
+		thisKey := key(keyErr)
+		bucket, ok := byKey[thisKey]
+		if !ok {
+			bucket = &ErrorBucket{Key: thisKey}
+			byKey[thisKey] = bucket
+			buckets = append(buckets, bucket)
+		}
+
+		bucket.Errs = append(bucket.Errs, thisErr)
+		if isOpErr {
+			bucket.OpNames = append(bucket.OpNames, opErr.OpName)
+		}
+	}
+
+	sort.SliceStable(buckets, func(i, j int) bool {
+		return buckets[i].Count() > buckets[j].Count()
+	})
+
+	result := make([]ErrorBucket, len(buckets))
+	for i, bucket := range buckets {
+		result[i] = *bucket
+	}
+	return result
+}
+
+// Summarize returns err with it's errors aggregated by key, for printing each group of
+// similar errors once. If key is nil, AggregateByMessage is used.
+func (err GroupErrors) Summarize(key AggregateKey) AggregatedErrors {
+	return AggregatedErrors{
+		Group:   err,
+		Buckets: err.Aggregate(key),
+	}
+}
+
+// AggregatedErrors is a GroupErrors summarized by GroupErrors.Summarize. It prints
+// every ErrorBucket once with it's count instead of printing every error. It unwraps
+// to Group, so errors.Is and errors.As behave the same as they do on Group.
+type AggregatedErrors struct {
+	// Group is the GroupErrors that was summarized.
+	Group GroupErrors
+	// Buckets are the result of Group.Aggregate.
+	Buckets []ErrorBucket
+}
+
+// Error implements builtins.error.
+func (err AggregatedErrors) Error() string {
+	if len(err.Buckets) == 0 {
+		return fmt.Sprintf("%v in 0 groups", err.Group.countText())
+	}
+	return fmt.Sprintf(
+		"%v in %v groups. most common: %v",
+		err.Group.countText(),
+		len(err.Buckets),
+		err.Buckets[0].summary(),
+	)
+}
+
+// Unwrap implements xerrors.Wrapper. Returns nil if Group holds no errors.
+func (err AggregatedErrors) Unwrap() error {
+	if len(err.Group.Errs) == 0 {
+		return nil
+	}
+	return err.Group
+}
+
+// summary describes the bucket on a single line, like "500 x connection refused".
+func (bucket ErrorBucket) summary() string {
+	return fmt.Sprintf("%v x %v", bucket.Count(), bucket.Key)
+}
@@ -0,0 +1,154 @@
+package pears_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/peake100/pears-go/pkg/pears"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"syscall"
+	"testing"
+)
+
+// newRepeatedErrs returns a GroupErrors with 7 ops failing with a refused connection,
+// 2 with io.EOF and 1 with io.ErrUnexpectedEOF.
+func newRepeatedErrs() pears.GroupErrors {
+	var errs []error
+	for i := 0; i < 10; i++ {
+		var err error
+		switch {
+		case i < 7:
+			err = fmt.Errorf("dial host %v: %w", i%2, syscall.ECONNREFUSED)
+		case i < 9:
+			err = io.EOF
+		default:
+			err = io.ErrUnexpectedEOF
+		}
+		errs = append(errs, pears.OpError{OpName: fmt.Sprint("op ", i), Err: err})
+	}
+
+	return pears.GroupErrors{MatchMode: pears.GroupMatchAny, Errs: errs}
+}
+
+func TestGroupErrors_Aggregate_ByMessage(t *testing.T) {
+	assert := assert.New(t)
+
+	buckets := newRepeatedErrs().Aggregate(nil)
+	if !assert.Len(buckets, 4, "buckets") {
+		t.FailNow()
+	}
+
+	assert.Equal("dial host 0: connection refused", buckets[0].Key, "first key")
+	assert.Equal(4, buckets[0].Count(), "first count")
+	assert.Equal(
+		[]string{"op 0", "op 2", "op 4", "op 6"}, buckets[0].OpNames, "first op names",
+	)
+
+	assert.Equal("dial host 1: connection refused", buckets[1].Key, "second key")
+	assert.Equal(3, buckets[1].Count(), "second count")
+
+	assert.Equal("EOF", buckets[2].Key, "third key")
+	assert.Equal([]string{"op 7", "op 8"}, buckets[2].OpNames, "third op names")
+
+	assert.Equal("unexpected EOF", buckets[3].Key, "fourth key")
+	assert.Equal(1, buckets[3].Count(), "fourth count")
+}
+
+func TestGroupErrors_Aggregate_ByIs(t *testing.T) {
+	assert := assert.New(t)
+
+	buckets := newRepeatedErrs().Aggregate(
+		pears.AggregateByIs(syscall.ECONNREFUSED, io.EOF),
+	)
+	if !assert.Len(buckets, 3, "buckets") {
+		t.FailNow()
+	}
+
+	assert.Equal("connection refused", fmt.Sprint(buckets[0].Key), "first key")
+	assert.Equal(7, buckets[0].Count(), "first count")
+	assert.Equal("EOF", fmt.Sprint(buckets[1].Key), "second key")
+	assert.Equal("unexpected EOF", buckets[2].Key, "unmatched key")
+}
+
+func TestGroupErrors_Aggregate_ByIs_SameMessage(t *testing.T) {
+	assert := assert.New(t)
+
+	first := errors.New("not found")
+	second := errors.New("not found")
+
+	groupErr := pears.GroupErrors{Errs: []error{
+		pears.OpError{OpName: "a", Err: first},
+		pears.OpError{OpName: "b", Err: second},
+		pears.OpError{OpName: "c", Err: fmt.Errorf("wrapped: %w", second)},
+		// Not either sentinel, but shares their message.
+		pears.OpError{OpName: "d", Err: errors.New("not found")},
+	}}
+
+	buckets := groupErr.Aggregate(pears.AggregateByIs(first, second))
+	if !assert.Len(buckets, 3, "each sentinel gets it's own bucket") {
+		t.FailNow()
+	}
+
+	assert.Equal([]string{"b", "c"}, buckets[0].OpNames, "second sentinel")
+	assert.Equal([]string{"a"}, buckets[1].OpNames, "first sentinel")
+	assert.Equal([]string{"d"}, buckets[2].OpNames, "unmatched error")
+	assert.Equal("not found", fmt.Sprint(buckets[0].Key), "key prints as message")
+}
+
+func TestGroupErrors_Aggregate_CustomKey(t *testing.T) {
+	groupErr := newRepeatedErrs()
+	groupErr.Errs = append(groupErr.Errs, errors.New("not an op error"))
+
+	buckets := groupErr.Aggregate(func(err error) interface{} {
+		if errors.Is(err, syscall.ECONNREFUSED) {
+			return "network"
+		}
+		return "other"
+	})
+
+	if !assert.Len(t, buckets, 2, "buckets") {
+		t.FailNow()
+	}
+	assert.Equal(t, "network", buckets[0].Key, "first key")
+	assert.Equal(t, 7, buckets[0].Count(), "first count")
+	assert.Equal(t, "other", buckets[1].Key, "second key")
+	assert.Equal(t, 4, buckets[1].Count(), "second count")
+	assert.Len(t, buckets[1].OpNames, 3, "only OpError names recorded")
+}
+
+func TestGroupErrors_Summarize(t *testing.T) {
+	assert := assert.New(t)
+
+	err := newRepeatedErrs().Summarize(pears.AggregateByIs(syscall.ECONNREFUSED))
+
+	assert.EqualError(
+		err,
+		"10 errors returned in 3 groups. most common: 7 x connection refused",
+	)
+	assert.ErrorIs(err, io.EOF, "unwraps to group")
+
+	expected := "10 errors returned in 3 groups:" +
+		"\n    7 x connection refused (ops: 'op 0', 'op 1', 'op 2', 'op 3', 'op 4'" +
+		" and 2 more)" +
+		"\n    2 x EOF (ops: 'op 7', 'op 8')" +
+		"\n    1 x unexpected EOF (ops: 'op 9')"
+	assert.Equal(expected, fmt.Sprintf("%+v", err), "%+v")
+
+	expected = "10 errors returned in 3 groups:" +
+		"\n    7 x connection refused (ops: 'op 0', 'op 1', 'op 2', 'op 3', 'op 4'" +
+		" and 2 more)" +
+		"\n    ... 2 more groups"
+	assert.Equal(expected, fmt.Sprintf("%+.1v", err), "%+.1v")
+	assert.Equal(err.Error(), fmt.Sprintf("%v", err), "%v")
+}
+
+func TestAggregatedErrors_Empty(t *testing.T) {
+	assert := assert.New(t)
+
+	empty := pears.GroupErrors{}.Summarize(nil)
+	assert.EqualError(empty, "0 errors returned in 0 groups")
+	assert.Equal("0 errors returned in 0 groups:", fmt.Sprintf("%+v", empty), "%+v")
+	assert.False(errors.Is(empty, io.EOF), "empty aggregate matches nothing")
+
+	assert.EqualError(pears.AggregatedErrors{}, "0 errors returned in 0 groups")
+}
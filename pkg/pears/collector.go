@@ -0,0 +1,190 @@
+package pears
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Collector gathers errors from many routines into a GroupErrors without launching
+// any routines itself. It is useful when routines are already managed elsewhere, like
+// in HTTP handlers or callbacks.
+//
+// Errors are wrapped in an OpError the same way Group wraps them, so the result of
+// Err looks the same as the result of Group.Wait.
+//
+// Collector must be created with a constructor function: NewCollector.
+type Collector struct {
+	// lock guards all fields below.
+	lock sync.Mutex
+
+	// errs stores the retained OpError values.
+	errs retainedErrs
+	// total is the number of errors added, including ones that were not retained.
+	total int
+	// typeCounts is the number of errors added for each error type, including ones
+	// that were not retained.
+	typeCounts map[string]int
+
+	// SETTINGS --------
+
+	// matchMode is the GroupMatchMode passed to the GroupErrors returned by Err.
+	matchMode GroupMatchMode
+	// displayLimit is the DisplayLimit passed to the GroupErrors returned by Err.
+	displayLimit int
+}
+
+// Add wraps err in an OpError named name and collects it. If err is nil, Add does
+// nothing.
+//
+// Add is safe to call from multiple routines.
+func (collector *Collector) Add(name string, err error) {
+	if err == nil {
+		return
+	}
+
+	collector.lock.Lock()
+	defer collector.lock.Unlock()
+
+	collector.addOpError(OpError{
+		OpName:      name,
+		Err:         err,
+		Attempts:    1,
+		attemptErrs: &[]error{err},
+		Ended:       time.Now(),
+		LaunchIndex: collector.total,
+	})
+}
+
+// collect stores an OpError from a Group, and returns it with it's CollectionIndex
+// set.
+func (collector *Collector) collect(err OpError) OpError {
+	collector.lock.Lock()
+	defer collector.lock.Unlock()
+
+	return collector.addOpError(err)
+}
+
+// addOpError stores err, setting it's CollectionIndex. The caller must hold lock.
+func (collector *Collector) addOpError(err OpError) OpError {
+	err.CollectionIndex = collector.total
+	collector.total++
+	collector.typeCounts[errTypeName(err.Err)]++
+
+	collector.errs.add(err, err.CollectionIndex)
+
+	return err
+}
+
+// errTypeName returns the key err is counted under in GroupErrors.TypeCounts.
+func errTypeName(err error) string {
+	return fmt.Sprintf("%T", err)
+}
+
+// Len returns the number of errors that have been added. This includes errors that
+// were not retained because of CollectorWithRetention.
+func (collector *Collector) Len() int {
+	collector.lock.Lock()
+	defer collector.lock.Unlock()
+
+	return collector.total
+}
+
+// Err returns nil if no errors have been added. Otherwise it returns the retained
+// errors in a GroupErrors, in the order they were added. GroupErrors.Total and
+// GroupErrors.TypeCounts count every error added, including ones that were not
+// retained. Errors may continue to be added after Err is called, and will be included
+// in the result of later calls.
+func (collector *Collector) Err() error {
+	collector.lock.Lock()
+	defer collector.lock.Unlock()
+
+	if collector.total == 0 {
+		return nil
+	}
+
+	// Copy our counts so further calls to Add do not modify the returned value. The
+	// retained errors are already copied by list.
+	typeCounts := make(map[string]int, len(collector.typeCounts))
+	for typeName, count := range collector.typeCounts {
+		typeCounts[typeName] = count
+	}
+
+	return GroupErrors{
+		MatchMode:    collector.matchMode,
+		Errs:         collector.errs.list(),
+		DisplayLimit: collector.displayLimit,
+		Total:        collector.total,
+		TypeCounts:   typeCounts,
+	}
+}
+
+// NewCollector creates a new *Collector for centrally collecting errors.
+//
+// The returned collector can be configured with opts.
+//
+// Options and defaults:
+//
+// - CollectorWithErrMode: GroupMatchFirst
+//
+// - CollectorWithRetention: RetentionPolicy{} (retain all errors)
+//
+// - CollectorWithDisplayLimit: 0 (no limit)
+func NewCollector(opts ...CollectorOption) *Collector {
+	collector := &Collector{
+		lock:         sync.Mutex{},
+		errs:         retainedErrs{},
+		total:        0,
+		typeCounts:   make(map[string]int),
+		matchMode:    GroupMatchFirst,
+		displayLimit: 0,
+	}
+
+	for _, opt := range opts {
+		opt(collector)
+	}
+
+	return collector
+}
+
+// CollectorOption defines an option for Collector.
+type CollectorOption = func(collector *Collector)
+
+// CollectorWithErrMode sets the GroupMatchMode of the GroupErrors returned by Err.
+//
+// Default: GroupMatchFirst.
+func CollectorWithErrMode(mode GroupMatchMode) CollectorOption {
+	return func(collector *Collector) {
+		collector.matchMode = mode
+	}
+}
+
+// CollectorWithMaxErrs caps the number of errors retained to the first maxErrs added.
+// Errors added after the cap is reached are counted by Len, but dropped. It is
+// shorthand for CollectorWithRetention(RetentionPolicy{Mode: RetainFirst, Max:
+// maxErrs}).
+//
+// Default: 0 (no limit).
+func CollectorWithMaxErrs(maxErrs int) CollectorOption {
+	return CollectorWithRetention(RetentionPolicy{Mode: RetainFirst, Max: maxErrs})
+}
+
+// CollectorWithRetention sets the RetentionPolicy that decides which errors are
+// retained. Errors that are not retained are counted by Len and by the Total and
+// TypeCounts of the GroupErrors returned by Err, but dropped.
+//
+// Default: RetentionPolicy{} (retain all errors).
+func CollectorWithRetention(policy RetentionPolicy) CollectorOption {
+	return func(collector *Collector) {
+		collector.errs.policy = policy
+	}
+}
+
+// CollectorWithDisplayLimit sets the DisplayLimit of the GroupErrors returned by Err.
+//
+// Default: 0 (no limit).
+func CollectorWithDisplayLimit(limit int) CollectorOption {
+	return func(collector *Collector) {
+		collector.displayLimit = limit
+	}
+}
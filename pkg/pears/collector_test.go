@@ -0,0 +1,87 @@
+package pears_test
+
+import (
+	"fmt"
+	"github.com/peake100/pears-go/pkg/pears"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"sync"
+	"testing"
+)
+
+func TestCollector_NoErrs(t *testing.T) {
+	collector := pears.NewCollector()
+	collector.Add("nil error", nil)
+
+	assert.Equal(t, 0, collector.Len(), "nil errors not counted")
+	assert.NoError(t, collector.Err(), "no error")
+}
+
+func TestCollector_Concurrent(t *testing.T) {
+	assert := assert.New(t)
+
+	collector := pears.NewCollector(pears.CollectorWithErrMode(pears.GroupMatchAny))
+
+	wg := new(sync.WaitGroup)
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			collector.Add(fmt.Sprint("handler", index), io.EOF)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(100, collector.Len(), "100 errors added")
+
+	err := collector.Err()
+	batchErrs := pears.GroupErrors{}
+	if !assert.ErrorAs(err, &batchErrs) {
+		t.FailNow()
+	}
+
+	assert.Equal(pears.GroupMatchAny, batchErrs.MatchMode, "match mode set")
+	assert.Len(batchErrs.Errs, 100, "all errors retained")
+
+	for i, thisErr := range batchErrs.Errs {
+		opErr := pears.OpError{}
+		if assert.ErrorAs(thisErr, &opErr, "error is OpError") {
+			assert.Equal(i, opErr.CollectionIndex, "collection index set")
+			assert.Contains(opErr.OpName, "handler", "op name set")
+		}
+		assert.ErrorIs(thisErr, io.EOF, "error unwraps to io.EOF")
+	}
+}
+
+func TestCollector_MaxErrs(t *testing.T) {
+	assert := assert.New(t)
+
+	collector := pears.NewCollector(pears.CollectorWithMaxErrs(2))
+	collector.Add("first", io.EOF)
+	collector.Add("second", io.ErrUnexpectedEOF)
+	collector.Add("third", io.ErrClosedPipe)
+
+	assert.Equal(3, collector.Len(), "all errors counted")
+
+	err := collector.Err()
+	batchErrs := pears.GroupErrors{}
+	if !assert.ErrorAs(err, &batchErrs) {
+		t.FailNow()
+	}
+	assert.Len(batchErrs.Errs, 2, "only 2 errors retained")
+	assert.ErrorIs(err, io.EOF, "first error matched")
+	assert.EqualError(err, "3 errors (2 retained). first: error during 'first': EOF")
+	assert.Equal(3, batchErrs.Total, "total")
+	assert.Equal(1, batchErrs.Dropped(), "dropped")
+}
+
+func TestCollector_ErrNotModifiedByAdd(t *testing.T) {
+	collector := pears.NewCollector()
+	collector.Add("first", io.EOF)
+
+	batchErrs := collector.Err().(pears.GroupErrors)
+	collector.Add("second", io.EOF)
+
+	assert.Len(t, batchErrs.Errs, 1, "earlier result not modified")
+	assert.Len(t, collector.Err().(pears.GroupErrors).Errs, 2, "later result updated")
+}
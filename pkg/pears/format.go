@@ -0,0 +1,193 @@
+package pears
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// formatIndent is written once per level of nesting when formatting with %+v.
+const formatIndent = "    "
+
+// These types share the fields of our error types without their methods, so %#v can
+// hand them to fmt's default Go-syntax formatting.
+type (
+	plainGroupErrors      GroupErrors
+	plainOpError          OpError
+	plainPanicError       PanicError
+	plainGoexitError      GoexitError
+	plainAggregatedErrors AggregatedErrors
+)
+
+// Format implements fmt.Formatter. %s and %v print the same summary as Error. %+v
+// prints every error on it's own indented line, including nested GroupErrors and the
+// stack traces of PanicError values. Only DisplayLimit errors are printed at each level
+// unless a precision is given, for instance %+.5v.
+func (err GroupErrors) Format(state fmt.State, verb rune) {
+	formatError(state, verb, err)
+}
+
+// Format implements fmt.Formatter. %s and %v print the same text as Error. %+v
+// expands a wrapped GroupErrors or PanicError the same way GroupErrors.Format does.
+func (err OpError) Format(state fmt.State, verb rune) {
+	formatError(state, verb, err)
+}
+
+// Format implements fmt.Formatter. %s and %v print the same text as Error. %+v adds
+// the indented stack trace on the lines that follow.
+func (err PanicError) Format(state fmt.State, verb rune) {
+	formatError(state, verb, err)
+}
+
+// Format implements fmt.Formatter. %s and %v print the same text as Error. %+v adds
+// the indented stack trace on the lines that follow.
+func (err GoexitError) Format(state fmt.State, verb rune) {
+	formatError(state, verb, err)
+}
+
+// Format implements fmt.Formatter. %s and %v print the same summary as Error. %+v
+// prints every bucket on it's own indented line with it's count and the names of up
+// to 5 of it's ops. Only Group.DisplayLimit buckets are printed unless a precision is
+// given, for instance %+.5v.
+func (err AggregatedErrors) Format(state fmt.State, verb rune) {
+	formatError(state, verb, err)
+}
+
+// formatError implements fmt.Formatter for our error types. %#v is left to fmt's
+// default Go-syntax representation.
+func formatError(state fmt.State, verb rune, err error) {
+	switch {
+	case verb == 'v' && state.Flag('+'):
+		limit, _ := state.Precision()
+		writeErrTree(state, err, 0, limit)
+	case verb == 'v' && state.Flag('#'):
+		writeGoSyntax(state, err)
+	case verb == 'q':
+		_, _ = fmt.Fprintf(state, "%q", err.Error())
+	default:
+		_, _ = io.WriteString(state, err.Error())
+	}
+}
+
+// writeErrTree writes err starting on the current line. Nested errors are written on
+// their own lines, indented to depth + 1. If limit is greater than 0 it overrides the
+// DisplayLimit of every GroupErrors in the tree.
+func writeErrTree(writer io.Writer, err error, depth int, limit int) {
+	switch typed := err.(type) {
+	case GroupErrors:
+		writeGroupTree(writer, typed, depth, limit)
+	case OpError:
+		_, _ = io.WriteString(writer, typed.prefix())
+		writeErrTree(writer, typed.Err, depth, limit)
+	case PanicError:
+		_, _ = io.WriteString(writer, typed.Error())
+		writeStack(writer, typed.StackTrace, depth+1)
+	case AggregatedErrors:
+		writeAggregatedTree(writer, typed, depth, limit)
+	case GoexitError:
+		_, _ = io.WriteString(writer, typed.Error())
+		writeStack(writer, typed.StackTrace, depth+1)
+	case nil:
+		_, _ = io.WriteString(writer, "<nil>")
+	default:
+		_, _ = io.WriteString(writer, err.Error())
+	}
+}
+
+// writeGoSyntax writes err as fmt's %#v verb would if err did not implement
+// fmt.Formatter.
+func writeGoSyntax(writer io.Writer, err error) {
+	var plain interface{}
+	switch typed := err.(type) {
+	case GroupErrors:
+		plain = plainGroupErrors(typed)
+	case OpError:
+		plain = plainOpError(typed)
+	case PanicError:
+		plain = plainPanicError(typed)
+	case GoexitError:
+		plain = plainGoexitError(typed)
+	case AggregatedErrors:
+		plain = plainAggregatedErrors(typed)
+	}
+
+	// Swap the name of our plain type back to the name of the original.
+	text := strings.TrimPrefix(fmt.Sprintf("%#v", plain), fmt.Sprintf("%T", plain))
+	_, _ = io.WriteString(writer, fmt.Sprintf("%T", err)+text)
+}
+
+// writeAggregatedTree writes an AggregatedErrors header followed by each of it's
+// buckets.
+func writeAggregatedTree(writer io.Writer, err AggregatedErrors, depth int, limit int) {
+	_, _ = fmt.Fprintf(
+		writer, "%v in %v groups:", err.Group.countText(), len(err.Buckets),
+	)
+
+	displayLimit := limit
+	if displayLimit <= 0 {
+		displayLimit = err.Group.DisplayLimit
+	}
+	shown := len(err.Buckets)
+	if displayLimit > 0 && displayLimit < shown {
+		shown = displayLimit
+	}
+
+	for _, bucket := range err.Buckets[:shown] {
+		line := bucket.summary()
+		if len(bucket.OpNames) > 0 {
+			names := bucket.OpNames
+			if len(names) > summaryOpNames {
+				names = names[:summaryOpNames]
+			}
+			line += fmt.Sprintf(" (ops: '%v'", strings.Join(names, "', '"))
+			if hidden := len(bucket.OpNames) - len(names); hidden > 0 {
+				line += fmt.Sprintf(" and %v more", hidden)
+			}
+			line += ")"
+		}
+		writeLine(writer, depth+1, line)
+	}
+
+	if hidden := len(err.Buckets) - shown; hidden > 0 {
+		writeLine(writer, depth+1, fmt.Sprintf("... %v more groups", hidden))
+	}
+}
+
+// writeStack writes each line of stack on it's own line, indented to depth.
+func writeStack(writer io.Writer, stack string, depth int) {
+	stack = strings.TrimRight(stack, "\n")
+	if stack == "" {
+		return
+	}
+	for _, line := range strings.Split(stack, "\n") {
+		writeLine(writer, depth, line)
+	}
+}
+
+// writeGroupTree writes a GroupErrors header followed by each of it's errors.
+func writeGroupTree(writer io.Writer, err GroupErrors, depth int, limit int) {
+	_, _ = io.WriteString(writer, err.countText()+":")
+
+	displayLimit := limit
+	if displayLimit <= 0 {
+		displayLimit = err.DisplayLimit
+	}
+	shown := len(err.Errs)
+	if displayLimit > 0 && displayLimit < shown {
+		shown = displayLimit
+	}
+
+	for _, thisErr := range err.Errs[:shown] {
+		writeLine(writer, depth+1, "")
+		writeErrTree(writer, thisErr, depth+1, limit)
+	}
+
+	if hidden := len(err.Errs) - shown; hidden > 0 {
+		writeLine(writer, depth+1, fmt.Sprintf("... %v more errors", hidden))
+	}
+}
+
+// writeLine starts a new line indented to depth, then writes text.
+func writeLine(writer io.Writer, depth int, text string) {
+	_, _ = io.WriteString(writer, "\n"+strings.Repeat(formatIndent, depth)+text)
+}
@@ -0,0 +1,159 @@
+package pears_test
+
+import (
+	"fmt"
+	"github.com/peake100/pears-go/pkg/pears"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestGroupErrors_Format(t *testing.T) {
+	err := pears.GroupErrors{
+		MatchMode: pears.GroupMatchFirst,
+		Errs: []error{
+			pears.OpError{OpName: "op1", Err: io.EOF},
+			pears.OpError{
+				OpName: "shard",
+				Err: pears.GroupErrors{
+					Errs: []error{
+						pears.OpError{OpName: "file1", Err: io.ErrUnexpectedEOF},
+						pears.OpError{OpName: "file2", Err: io.ErrClosedPipe},
+					},
+				},
+			},
+			pears.OpError{
+				OpName: "panics",
+				Err: pears.PanicError{
+					Recovered:    "boom",
+					RecoveredErr: fmt.Errorf("boom"),
+					StackTrace:   "goroutine 1 [running]:\nmain.main()\n",
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		Name     string
+		Format   string
+		Err      error
+		Expected string
+	}{
+		{
+			Name:     "Summary_v",
+			Format:   "%v",
+			Err:      err,
+			Expected: "3 errors returned. first: error during 'op1': EOF",
+		},
+		{
+			Name:     "Summary_s",
+			Format:   "%s",
+			Err:      err,
+			Expected: "3 errors returned. first: error during 'op1': EOF",
+		},
+		{
+			Name:     "Quoted",
+			Format:   "%q",
+			Err:      err.Errs[0],
+			Expected: `"error during 'op1': EOF"`,
+		},
+		{
+			Name:   "Verbose",
+			Format: "%+v",
+			Err:    err,
+			Expected: strings.Join([]string{
+				"3 errors returned:",
+				"    error during 'op1': EOF",
+				"    error during 'shard': 2 errors returned:",
+				"        error during 'file1': unexpected EOF",
+				"        error during 'file2': io: read/write on closed pipe",
+				"    error during 'panics': panic recovered: boom",
+				"        goroutine 1 [running]:",
+				"        main.main()",
+			}, "\n"),
+		},
+		{
+			Name:   "Verbose_Precision",
+			Format: "%+.1v",
+			Err:    err,
+			Expected: strings.Join([]string{
+				"3 errors returned:",
+				"    error during 'op1': EOF",
+				"    ... 2 more errors",
+			}, "\n"),
+		},
+		{
+			Name:     "Verbose_NilErr",
+			Format:   "%+v",
+			Err:      pears.OpError{OpName: "op1"},
+			Expected: "error during 'op1': <nil>",
+		},
+		{
+			Name:   "Verbose_OpError",
+			Format: "%+v",
+			Err:    err.Errs[1],
+			Expected: strings.Join([]string{
+				"error during 'shard': 2 errors returned:",
+				"    error during 'file1': unexpected EOF",
+				"    error during 'file2': io: read/write on closed pipe",
+			}, "\n"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, fmt.Sprintf(tc.Format, tc.Err))
+		})
+	}
+}
+
+func TestGroupErrors_Format_DisplayLimit(t *testing.T) {
+	err := pears.GroupErrors{
+		Errs: []error{
+			io.EOF,
+			io.ErrUnexpectedEOF,
+			io.ErrClosedPipe,
+		},
+		DisplayLimit: 2,
+	}
+
+	expected := strings.Join([]string{
+		"3 errors returned:",
+		"    EOF",
+		"    unexpected EOF",
+		"    ... 1 more errors",
+	}, "\n")
+
+	assert.Equal(t, expected, fmt.Sprintf("%+v", err))
+}
+
+func TestFormat_GoSyntax(t *testing.T) {
+	assert := assert.New(t)
+
+	err := pears.GroupErrors{
+		MatchMode: pears.GroupMatchAny,
+		Errs: []error{
+			pears.OpError{OpName: "op1"},
+			pears.OpError{OpName: "panics", Err: pears.PanicError{Recovered: "boom"}},
+		},
+	}
+
+	// %#v should print fmt's Go-syntax representation, including for nested errors,
+	// rather than the text of Error.
+	formatted := fmt.Sprintf("%#v", err)
+	assert.True(
+		strings.HasPrefix(formatted, "pears.GroupErrors{MatchMode:1, Errs:[]error{"),
+		"group errors: %v",
+		formatted,
+	)
+	assert.Contains(formatted, `pears.OpError{OpName:"op1", Err:error(nil)`, "op error")
+	assert.Contains(formatted, `pears.PanicError{Recovered:"boom"`, "panic error")
+
+	formatted = fmt.Sprintf("%#v", pears.GoexitError{StackTrace: "trace"})
+	assert.True(
+		strings.HasPrefix(formatted, `pears.GoexitError{StackTrace:"trace"`),
+		"goexit error: %v",
+		formatted,
+	)
+}
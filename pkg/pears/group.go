@@ -2,10 +2,46 @@ package pears
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
+// ErrOpNotStarted is wrapped in an OpError for every op that was still waiting in the
+// WithMaxConcurrency queue when the Group's context was cancelled. These ops are never
+// run.
+var ErrOpNotStarted = errors.New("op not started: group context cancelled while queued")
+
+// WaitAbandonedError is returned by Group.WaitContext when it's context is done before
+// every operation has completed.
+type WaitAbandonedError struct {
+	// Err is the error of the context passed to WaitContext.
+	Err error
+	// Running holds the names of the ops that were still running, in launch order.
+	Running []string
+}
+
+// Error implements builtins.error.
+func (err WaitAbandonedError) Error() string {
+	if len(err.Running) == 0 {
+		return fmt.Sprint("wait abandoned: ", err.Err)
+	}
+	return fmt.Sprintf(
+		"wait abandoned with %v ops still running ['%v']: %v",
+		len(err.Running),
+		strings.Join(err.Running, "', '"),
+		err.Err,
+	)
+}
+
+// Unwrap implements xerrors.Wrapper.
+func (err WaitAbandonedError) Unwrap() error {
+	return err.Err
+}
+
 // Group runs a number of concurrent operations and collects their errors.
 //
 // Group takes some inspirations from: https://pkg.go.dev/golang.org/x/sync/errgroup,
@@ -21,6 +57,8 @@ import (
 //   readability.
 //
 // - Group must be created with a constructor function: NewGroup.
+//
+// - Panics in ops are recovered and returned as a PanicError by default.
 type Group struct {
 	// ctx is the main context we will pass to all Go ops.
 	ctx context.Context
@@ -32,9 +70,23 @@ type Group struct {
 	//
 	// 1 = Wait has been called.
 	joined int32
+	// aborted will be atomically set before cancel is called because of an op error or
+	// an abandoned WaitContext, so ops can tell the group's own abort apart from a
+	// cancelled parent context.
+	//
+	// 1 = the group has aborted.
+	aborted int32
+
+	// launched is atomically incremented every time an op is launched to assign it's
+	// launch index.
+	launched int64
+	// finished is atomically incremented every time an op finishes, successfully or
+	// not. It is only incremented while holding queueLock, so it is consistent with
+	// inFlight and failed.
+	finished int64
 
 	// opErrors receives errors encountered by ops run in Go for collection.
-	opErrors chan error
+	opErrors chan OpError
 	// opsDone will be added to for every call to Go before returning, and
 	// waited on before Wait exits.
 	opsDone sync.WaitGroup
@@ -42,21 +94,62 @@ type Group struct {
 	// routine to start wrapping up.
 	joinCalled chan struct{}
 	// errorsCollected will be closed by the error collection routine once all errors
-	// are collected to signal that Wait can process them.
+	// are collected and result has been set. It is returned by Done.
 	errorsCollected chan struct{}
 
+	// queueLock guards queue, running, inFlight and failed.
+	queueLock sync.Mutex
+	// queue holds ops that are waiting for a free slot when maxConcurrency has been
+	// reached.
+	queue []*groupOp
+	// running is the number of ops currently holding a concurrency slot.
+	running int
+	// inFlight holds every op that has started but not yet finished, including
+	// unfinished sub-groups.
+	inFlight map[*groupOp]struct{}
+	// failed is the number of ops that have finished with an error.
+	failed int
+
 	// SETTINGS --------
 
-	// abortOnErr will cause cancel to be called as soon as a routine op returns an
-	// error.
-	abortOnErr bool
-	// errMode is
+	// abortPolicy is consulted for every collected error until the group aborts. If it
+	// returns true, cancel is called. If nil, the group never aborts.
+	abortPolicy AbortPolicy
+	// errMode is the GroupMatchMode passed to the GroupErrors returned by Wait.
 	errMode GroupMatchMode
+	// displayLimit is the DisplayLimit passed to the GroupErrors returned by Wait.
+	displayLimit int
+	// retention is the RetentionPolicy of our collector.
+	retention RetentionPolicy
+	// observer is notified of lifecycle events.
+	observer multiObserver
+	// onError holds callbacks that are passed every collected error.
+	onError []func(err OpError)
+	// recoverPanics will cause ops to be run with CatchPanic.
+	recoverPanics bool
+	// retry is the default RetryPolicy for ops.
+	retry RetryPolicy
+	// maxConcurrency is the maximum number of ops that may run at once. 0 means there
+	// is no limit.
+	maxConcurrency int
 
 	// RESULTS ----------
 
-	// collectedErrs stores opErrors as they are returned by operations.
-	collectedErrs []error
+	// collector stores opErrors as they are returned by operations.
+	collector *Collector
+	// result is the final error of the Group. It is set before errorsCollected is
+	// closed.
+	result error
+
+	// SUBGROUPS --------
+
+	// parent is the Group this Group was created from by SubGroup. nil if this is not a
+	// sub-group.
+	parent *Group
+	// parentOp describes this Group as an op of parent.
+	parentOp *groupOp
+	// created is when this Group was created.
+	created time.Time
 }
 
 // collectErrors will be run in it's own routine and collect the errors from our
@@ -70,9 +163,13 @@ func (runner *Group) collectErrors() {
 	go func() {
 		defer close(collectionDone)
 		for err := range runner.opErrors {
-			runner.collectedErrs = append(runner.collectedErrs, err)
-			if runner.abortOnErr {
-				runner.cancel()
+			err = runner.collector.collect(err)
+			if runner.shouldAbort(err) {
+				runner.abort()
+				runner.observer.AbortTriggered(err)
+			}
+			for _, onError := range runner.onError {
+				onError(err)
 			}
 		}
 	}()
@@ -88,62 +185,500 @@ func (runner *Group) collectErrors() {
 
 	// Wait for collection to be done.
 	<-collectionDone
+
+	// Store our result and release our context before signaling that we are done.
+	runner.result = runner.collector.Err()
+	runner.cancel()
+
+	if runner.parent != nil {
+		runner.parent.finishSubGroup(runner, runner.result)
+	}
+}
+
+// shouldAbort consults abortPolicy about a newly collected err.
+func (runner *Group) shouldAbort(err OpError) bool {
+	if runner.abortPolicy == nil || atomic.LoadInt32(&runner.aborted) == 1 {
+		return false
+	}
+
+	counts := AbortCounts{
+		Launched: int(atomic.LoadInt64(&runner.launched)),
+		Finished: int(atomic.LoadInt64(&runner.finished)),
+		Failed:   runner.collector.Len(),
+	}
+	return runner.abortPolicy(err, counts)
+}
+
+// abort marks the group as aborted, then cancels it's context.
+func (runner *Group) abort() {
+	atomic.StoreInt32(&runner.aborted, 1)
+	runner.cancel()
+}
+
+// isFallout returns true if err was caused by the group or one of it's parents
+// aborting, rather than being a root cause of failure.
+func (runner *Group) isFallout(err error) bool {
+	if !runner.hasAborted() {
+		return false
+	}
+	return errors.Is(err, context.Canceled) || errors.Is(err, ErrOpNotStarted)
+}
+
+// hasAborted returns true if the group or any of it's parents has aborted. A parent's
+// abort cancels our context, so it counts as our own.
+func (runner *Group) hasAborted() bool {
+	for group := runner; group != nil; group = group.parent {
+		if atomic.LoadInt32(&group.aborted) == 1 {
+			return true
+		}
+	}
+	return false
 }
 
 // Go launches op in it's own routine and sends any returned errors to be
 // collected.
 //
 // Go will panic if called after Wait.
-func (runner *Group) Go(op func(ctx context.Context) error) {
-	runner.GoNamed("[ROUTINE]", op)
+func (runner *Group) Go(op func(ctx context.Context) error, opts ...OpOption) {
+	runner.GoNamed("[ROUTINE]", op, opts...)
 }
 
 // GoNamed can be used to give your routine a name.
-func (runner *Group) GoNamed(name string, op func(ctx context.Context) error) {
+//
+// If WithMaxConcurrency was set and the limit has been reached, op is queued and will
+// be launched once a running op returns.
+//
+// opts configure this op only, and override any equivalent GroupOption.
+func (runner *Group) GoNamed(
+	name string, op func(ctx context.Context) error, opts ...OpOption,
+) {
 	if !atomic.CompareAndSwapInt32(&runner.joined, 0, 0) {
 		panic("Group.Go called after Group.Wait")
 	}
 
 	runner.opsDone.Add(1)
 
-	go func() {
-		defer runner.opsDone.Done()
-		err := op(runner.ctx)
-		if err == nil {
+	queued := &groupOp{
+		name:  name,
+		run:   op,
+		index: int(atomic.AddInt64(&runner.launched, 1) - 1),
+		retry: runner.retry,
+	}
+	for _, opt := range opts {
+		opt(queued)
+	}
+
+	runner.observer.OpQueued(queued.info())
+
+	if !runner.acquireSlot(queued) {
+		return
+	}
+
+	go runner.runWorker(queued)
+}
+
+// groupOp holds an op passed to Group.GoNamed until it is run.
+type groupOp struct {
+	// name is the name of the op.
+	name string
+	// run is the op function.
+	run func(ctx context.Context) error
+	// index is the launch index of the op.
+	index int
+	// started is when the op was started. Zero until the op starts.
+	started time.Time
+	// queued is true if the op had to wait for a concurrency slot.
+	queued bool
+	// retry is the RetryPolicy for the op.
+	retry RetryPolicy
+	// timeout is the maximum duration of each attempt of the op. 0 means no timeout.
+	timeout time.Duration
+	// deadline is the time by which the op must complete. Zero means no deadline.
+	deadline time.Time
+	// attemptErrs holds the errors returned by the op's attempts so far.
+	attemptErrs []error
+}
+
+// info returns the OpInfo for op.
+func (op *groupOp) info() OpInfo {
+	return OpInfo{
+		Name:        op.name,
+		LaunchIndex: op.index,
+		Started:     op.started,
+	}
+}
+
+// acquireSlot reserves a concurrency slot for op. If no slot is available, op is added
+// to the queue and false is returned.
+func (runner *Group) acquireSlot(op *groupOp) bool {
+	runner.queueLock.Lock()
+	defer runner.queueLock.Unlock()
+
+	if runner.maxConcurrency > 0 && runner.running >= runner.maxConcurrency {
+		op.queued = true
+		runner.queue = append(runner.queue, op)
+		return false
+	}
+
+	runner.running++
+	return true
+}
+
+// nextQueued pops the next op off the queue for a worker that has finished it's
+// current op. If the queue is empty, nil is returned and the worker's slot is released.
+func (runner *Group) nextQueued() *groupOp {
+	runner.queueLock.Lock()
+	defer runner.queueLock.Unlock()
+
+	if len(runner.queue) == 0 {
+		runner.running--
+		return nil
+	}
+
+	op := runner.queue[0]
+	runner.queue[0] = nil
+	runner.queue = runner.queue[1:]
+	return op
+}
+
+// runWorker runs op, then keeps running queued ops until the queue is empty.
+func (runner *Group) runWorker(op *groupOp) {
+	for op != nil {
+		runner.runOp(op)
+		op = runner.nextQueued()
+	}
+}
+
+// runOp runs a single op and sends any returned error to be collected.
+func (runner *Group) runOp(op *groupOp) {
+	defer runner.opsDone.Done()
+
+	// returned is set if execOp returns. If it is not set when we exit, the op called
+	// runtime.Goexit or panicked, and this routine is exiting.
+	returned := false
+	defer func() {
+		if returned {
 			return
 		}
+		// recover returns nil for a Goexit. Panics only get here when recoverPanics is
+		// not set, so pass them on to crash the program.
+		if recovered := recover(); recovered != nil {
+			panic(recovered)
+		}
+		runner.finishGoexit(op)
+	}()
 
-		// Wrap this error in a batch error.
-		err = OpError{
-			OpName: name,
-			Err:    err,
+	runner.execOp(op)
+	returned = true
+}
+
+// execOp starts op and runs it's attempts, then finishes it.
+func (runner *Group) execOp(op *groupOp) {
+	if op.queued && runner.ctx.Err() != nil {
+		// Ops that were waiting for a slot when the group was cancelled are never
+		// started.
+		runner.finishOp(op, &OpError{
+			OpName:      op.name,
+			Err:         ErrOpNotStarted,
+			Fallout:     runner.isFallout(ErrOpNotStarted),
+			Ended:       time.Now(),
+			LaunchIndex: op.index,
+		})
+		return
+	}
+
+	runner.startOp(op, time.Now())
+
+	attemptErrs, timedOut := runner.attemptOp(op)
+	if len(attemptErrs) == 0 {
+		runner.finishOp(op, nil)
+		return
+	}
+
+	// Wrap this error in a batch error.
+	err := attemptErrs[len(attemptErrs)-1]
+	runner.finishOp(op, &OpError{
+		OpName:      op.name,
+		Err:         err,
+		Attempts:    len(attemptErrs),
+		attemptErrs: &attemptErrs,
+		TimedOut:    timedOut,
+		Fallout:     runner.isFallout(err),
+		Started:     op.started,
+		Ended:       time.Now(),
+		LaunchIndex: op.index,
+	})
+}
+
+// startOp records that op started at started.
+func (runner *Group) startOp(op *groupOp, started time.Time) {
+	runner.queueLock.Lock()
+	op.started = started
+	runner.inFlight[op] = struct{}{}
+	runner.queueLock.Unlock()
+
+	runner.observer.OpStarted(op.info())
+}
+
+// finishOp records that op has finished, and sends opErr to be collected if it is not
+// nil.
+func (runner *Group) finishOp(op *groupOp, opErr *OpError) {
+	runner.queueLock.Lock()
+	delete(runner.inFlight, op)
+	atomic.AddInt64(&runner.finished, 1)
+	if opErr != nil {
+		runner.failed++
+	}
+	runner.queueLock.Unlock()
+
+	var duration time.Duration
+	if !op.started.IsZero() {
+		duration = time.Since(op.started)
+	}
+
+	if opErr == nil {
+		runner.observer.OpFinished(op.info(), duration, nil)
+		return
+	}
+
+	runner.observer.OpFinished(op.info(), duration, *opErr)
+	runner.opErrors <- *opErr
+}
+
+// attemptOp runs op until it succeeds or it's RetryPolicy gives up, and returns the
+// errors from every attempt. If op eventually succeeds, nil is returned. timedOut
+// reports whether the final attempt exceeded the op's timeout or deadline.
+func (runner *Group) attemptOp(op *groupOp) (attemptErrs []error, timedOut bool) {
+	for attempt := 1; ; attempt++ {
+		var err error
+		timedOut, err = runner.callOp(op)
+		if err == nil {
+			return nil, false
 		}
 
-		runner.opErrors <- err
-	}()
+		op.attemptErrs = append(op.attemptErrs, err)
+		if !op.retry.shouldRetry(attempt, err) {
+			return op.attemptErrs, timedOut
+		}
+
+		// Stop retrying if the group is cancelled during our backoff.
+		if !sleepCtx(runner.ctx, op.retry.Backoff.Delay(attempt)) {
+			return op.attemptErrs, timedOut
+		}
+	}
+}
+
+// callOp calls op's function with a context derived from the Group's context,
+// recovering any panics if recoverPanics is set. timedOut reports whether the op's own
+// timeout or deadline was exceeded.
+func (runner *Group) callOp(op *groupOp) (timedOut bool, err error) {
+	ctx, cancel := op.context(runner.ctx)
+	defer cancel()
+
+	if !runner.recoverPanics {
+		err = op.run(ctx)
+	} else {
+		_, err = recoverPanic(func() (struct{}, error) {
+			return struct{}{}, op.run(ctx)
+		})
+	}
+
+	// Only report a timeout if the group itself was not cancelled, so ops aborted by
+	// a sibling are not mistaken for ops that were too slow.
+	timedOut = err != nil &&
+		errors.Is(ctx.Err(), context.DeadlineExceeded) &&
+		runner.ctx.Err() == nil
+
+	return timedOut, err
+}
+
+// finishGoexit finishes op with a GoexitError after it called runtime.Goexit. The
+// exiting routine was running op as a worker, so it's slot is handed on to the next
+// queued op.
+func (runner *Group) finishGoexit(op *groupOp) {
+	var err error = newGoexitError()
+	attemptErrs := append(op.attemptErrs, err)
+
+	runner.finishOp(op, &OpError{
+		OpName:      op.name,
+		Err:         err,
+		Attempts:    len(attemptErrs),
+		attemptErrs: &attemptErrs,
+		Fallout:     runner.isFallout(err),
+		Started:     op.started,
+		Ended:       time.Now(),
+		LaunchIndex: op.index,
+	})
+
+	if next := runner.nextQueued(); next != nil {
+		go runner.runWorker(next)
+	}
+}
+
+// context derives the context for a single attempt of op from the Group's context.
+func (op *groupOp) context(groupCtx context.Context) (context.Context, context.CancelFunc) {
+	deadline := op.deadline
+	if op.timeout > 0 {
+		timeoutDeadline := time.Now().Add(op.timeout)
+		if deadline.IsZero() || timeoutDeadline.Before(deadline) {
+			deadline = timeoutDeadline
+		}
+	}
+
+	if deadline.IsZero() {
+		return context.WithCancel(groupCtx)
+	}
+	return context.WithDeadline(groupCtx, deadline)
 }
 
 // Wait waits until all operations launched by Go complete. If any errors
 // are returned by operations, they will be returned as OpError values in a
 // GroupErrors container.
 //
-// Wait will panic if called multiple times. Group cannot be reused.
+// Wait will panic if called multiple times, or after WaitContext. Group cannot be
+// reused.
 func (runner *Group) Wait() error {
-	defer runner.cancel()
+	runner.join()
+
+	<-runner.errorsCollected
+	err := runner.result
+
+	runner.observer.WaitReturned(err)
+	return err
+}
+
+// WaitContext is like Wait, but returns early with a WaitAbandonedError if ctx is
+// done before all operations complete. The WaitAbandonedError lists the ops that were
+// still running.
+//
+// When WaitContext returns early, the Group's context is cancelled so ops can wind
+// down. The Group keeps collecting errors in the background: Done will be closed and
+// Err will return the Group's final result once every op has returned.
+//
+// WaitContext will panic if called multiple times, or after Wait.
+func (runner *Group) WaitContext(ctx context.Context) error {
+	runner.join()
+
+	var err error
+	select {
+	case <-runner.errorsCollected:
+		err = runner.result
+	case <-ctx.Done():
+		err = runner.abandon(ctx.Err())
+	}
+
+	runner.observer.WaitReturned(err)
+	return err
+}
+
+// join marks the Group as joined and signals the error collection routine to wrap up
+// once all ops are done. It panics if the Group has already been joined.
+func (runner *Group) join() {
 	if !atomic.CompareAndSwapInt32(&runner.joined, 0, 1) {
-		panic("Group.Wait called multiple times")
+		panic("Group.Wait or Group.WaitContext called multiple times")
 	}
 	close(runner.joinCalled)
+}
 
-	<-runner.errorsCollected
-	if len(runner.collectedErrs) == 0 {
+// abandon cancels the Group's context and returns a WaitAbandonedError for cause. If
+// the Group finished while we were abandoning it, the Group's result is returned
+// instead.
+func (runner *Group) abandon(cause error) error {
+	running := runner.Running()
+	names := make([]string, len(running))
+	for i, info := range running {
+		names[i] = info.Name
+	}
+
+	err := WaitAbandonedError{
+		Err:     cause,
+		Running: names,
+	}
+	runner.abort()
+
+	// Prefer the real result if every op finished before we could report them as
+	// running.
+	select {
+	case <-runner.errorsCollected:
+		return runner.result
+	default:
+		return err
+	}
+}
+
+// Done returns a channel that is closed once Wait or WaitContext has been called and
+// every operation has completed. It is never closed if neither has been called.
+func (runner *Group) Done() <-chan struct{} {
+	return runner.errorsCollected
+}
+
+// Err returns the same result as Wait once Done has been closed. It does not block:
+// before Done is closed, Err returns nil.
+func (runner *Group) Err() error {
+	select {
+	case <-runner.errorsCollected:
+		return runner.result
+	default:
 		return nil
 	}
+}
+
+// SubGroup creates a child Group for running a nested batch of operations. The
+// child's context is derived from runner's context, so aborting runner also aborts the
+// child. The child's own abort policy only cancels the child and any of it's
+// descendants.
+//
+// The child is tracked by runner like an op named name: the result of the child's
+// Wait is collected by runner as an OpError wrapping the child's GroupErrors, and can
+// cause runner to abort like any other op error. Runner's Wait will block until the
+// child's Wait has been called, so every SubGroup must be waited on.
+//
+// The child does not take one of runner's WithMaxConcurrency slots, as it is run by
+// the caller rather than by runner, so it is never queued. Ops launched on the child
+// are only limited by the child's own options.
+//
+// The child is configured by opts, not by runner's options. SubGroup will panic if
+// called after Wait.
+func (runner *Group) SubGroup(name string, opts ...GroupOption) *Group {
+	if !atomic.CompareAndSwapInt32(&runner.joined, 0, 0) {
+		panic("Group.SubGroup called after Group.Wait")
+	}
+
+	runner.opsDone.Add(1)
+
+	child := NewGroup(runner.ctx, opts...)
+	child.parent = runner
+	child.parentOp = &groupOp{
+		name:  name,
+		index: int(atomic.AddInt64(&runner.launched, 1) - 1),
+	}
+
+	runner.observer.OpQueued(child.parentOp.info())
+	runner.startOp(child.parentOp, child.created)
+
+	return child
+}
+
+// finishSubGroup collects the result of a child Group's Wait as if child were an op.
+func (runner *Group) finishSubGroup(child *Group, err error) {
+	defer runner.opsDone.Done()
 
-	return GroupErrors{
-		MatchMode: runner.errMode,
-		Errs:      runner.collectedErrs,
+	op := child.parentOp
+	if err == nil {
+		runner.finishOp(op, nil)
+		return
 	}
+
+	runner.finishOp(op, &OpError{
+		OpName:      op.name,
+		Err:         err,
+		Attempts:    1,
+		attemptErrs: &[]error{err},
+		Fallout:     runner.isFallout(err),
+		Started:     op.started,
+		Ended:       time.Now(),
+		LaunchIndex: op.index,
+	})
 }
 
 // NewGroup creates a new *Group for running concurrent operations and
@@ -159,6 +694,12 @@ func (runner *Group) Wait() error {
 // - WithAbortOnError: true
 //
 // - WithErrMode: GroupMatchFirst
+//
+// - WithPanicRecovery: true
+//
+// - WithMaxConcurrency: 0 (no limit)
+//
+// - WithRetry: RetryPolicy{} (no retries)
 func NewGroup(
 	ctx context.Context,
 	opts ...GroupOption,
@@ -170,13 +711,31 @@ func NewGroup(
 		ctx:             managerCtx,
 		cancel:          cancel,
 		joined:          0,
-		opErrors:        make(chan error, 1),
+		launched:        0,
+		finished:        0,
+		opErrors:        make(chan OpError, 1),
 		opsDone:         sync.WaitGroup{},
 		joinCalled:      make(chan struct{}),
 		errorsCollected: make(chan struct{}),
-		abortOnErr:      true,
+		queueLock:       sync.Mutex{},
+		queue:           nil,
+		running:         0,
+		inFlight:        make(map[*groupOp]struct{}),
+		failed:          0,
+		abortPolicy:     AbortOnFirstError(),
 		errMode:         GroupMatchFirst,
-		collectedErrs:   make([]error, 0),
+		displayLimit:    0,
+		retention:       RetentionPolicy{},
+		observer:        nil,
+		onError:         nil,
+		recoverPanics:   true,
+		retry:           RetryPolicy{},
+		maxConcurrency:  0,
+		collector:       nil,
+		result:          nil,
+		parent:          nil,
+		parentOp:        nil,
+		created:         time.Now(),
 	}
 
 	// Apply our options.
@@ -184,6 +743,12 @@ func NewGroup(
 		opt(group)
 	}
 
+	group.collector = NewCollector(
+		CollectorWithErrMode(group.errMode),
+		CollectorWithDisplayLimit(group.displayLimit),
+		CollectorWithRetention(group.retention),
+	)
+
 	// Launch the error collection routine.
 	go group.collectErrors()
 
@@ -194,12 +759,31 @@ func NewGroup(
 // GroupOption defines an option for Group.
 type GroupOption = func(group *Group)
 
+// OpOption defines an option for a single op launched with Group.Go or Group.GoNamed.
+type OpOption = func(op *groupOp)
+
 // WithAbortOnError sets whether the Group should abort on the first encountered error.
+// It is shorthand for WithAbortPolicy(AbortOnFirstError()) when abort is true, and
+// WithAbortPolicy(nil) when abort is false.
 //
 // Default: true.
 func WithAbortOnError(abort bool) GroupOption {
 	return func(group *Group) {
-		group.abortOnErr = abort
+		if abort {
+			group.abortPolicy = AbortOnFirstError()
+		} else {
+			group.abortPolicy = nil
+		}
+	}
+}
+
+// WithAbortPolicy sets the AbortPolicy the Group uses to decide whether to abort when
+// an error is collected. A nil policy causes the Group to never abort.
+//
+// Default: AbortOnFirstError().
+func WithAbortPolicy(policy AbortPolicy) GroupOption {
+	return func(group *Group) {
+		group.abortPolicy = policy
 	}
 }
 
@@ -211,3 +795,130 @@ func WithErrMode(mode GroupMatchMode) GroupOption {
 		group.errMode = mode
 	}
 }
+
+// WithMaxConcurrency limits the number of ops that may run at once to n. Ops launched
+// beyond the limit are queued and started in the order they were launched as running
+// ops return.
+//
+// Queued ops that have not been started when the Group's context is cancelled will
+// never run. Instead, an OpError wrapping ErrOpNotStarted is collected for each of
+// them.
+//
+// Sub-groups created with SubGroup do not count toward the limit, so a Group limited
+// to n ops may run more than n sub-groups at once.
+//
+// Default: 0 (no limit).
+func WithMaxConcurrency(n int) GroupOption {
+	return func(group *Group) {
+		group.maxConcurrency = n
+	}
+}
+
+// WithPanicRecovery sets whether ops should recover panics like CatchPanic. When true, a
+// panicking op will return an OpError wrapping a PanicError, which is collected and
+// aborts the Group like any other error. When false, a panicking op will crash the
+// program.
+//
+// An op that calls runtime.Goexit returns an OpError wrapping a GoexitError whatever
+// this is set to.
+//
+// Unlike CatchPanic, recovery happens on the routine the op already runs on, so no
+// extra goroutine is started per op and the trace of a PanicError includes the
+// Group's own frames.
+//
+// Default: true.
+func WithPanicRecovery(recoverPanics bool) GroupOption {
+	return func(group *Group) {
+		group.recoverPanics = recoverPanics
+	}
+}
+
+// WithRetry sets the default RetryPolicy for every op launched by the Group. It can be
+// overridden for individual ops with OpWithRetry.
+//
+// Ops are retried before their error is collected, so a retried op will not cause the
+// Group to abort until it's final attempt fails.
+//
+// Default: RetryPolicy{} (no retries).
+func WithRetry(policy RetryPolicy) GroupOption {
+	return func(group *Group) {
+		group.retry = policy
+	}
+}
+
+// OpWithRetry sets the RetryPolicy for a single op, overriding WithRetry.
+func OpWithRetry(policy RetryPolicy) OpOption {
+	return func(op *groupOp) {
+		op.retry = policy
+	}
+}
+
+// OpWithTimeout limits each attempt of a single op to timeout. The op's context is
+// derived from the Group's context, and is cancelled with context.DeadlineExceeded
+// once timeout elapses. If the op returns an error after it's timeout is exceeded,
+// OpError.TimedOut will be true.
+//
+// When combined with OpWithRetry, every attempt gets a fresh timeout.
+func OpWithTimeout(timeout time.Duration) OpOption {
+	return func(op *groupOp) {
+		op.timeout = timeout
+	}
+}
+
+// OpWithDeadline requires a single op to complete by deadline. It behaves like
+// OpWithTimeout, except that deadline is shared by all attempts of the op.
+func OpWithDeadline(deadline time.Time) OpOption {
+	return func(op *groupOp) {
+		op.deadline = deadline
+	}
+}
+
+// WithDisplayLimit sets the DisplayLimit passed to the GroupErrors returned by Wait.
+//
+// Default: 0 (no limit).
+func WithDisplayLimit(limit int) GroupOption {
+	return func(group *Group) {
+		group.displayLimit = limit
+	}
+}
+
+// WithRetention sets the RetentionPolicy that decides which collected errors are kept
+// in the GroupErrors returned by Wait. Errors that are not retained are still counted
+// by GroupErrors.Total and GroupErrors.TypeCounts, still count towards the Group's
+// AbortPolicy, and are still passed to WithOnError callbacks.
+//
+// Default: RetentionPolicy{} (retain all errors).
+func WithRetention(policy RetentionPolicy) GroupOption {
+	return func(group *Group) {
+		group.retention = policy
+	}
+}
+
+// WithObserver adds observer to the GroupObserver values notified of the Group's
+// lifecycle events. This option can be passed multiple times to add multiple
+// observers, which are notified in the order they were added.
+func WithObserver(observer GroupObserver) GroupOption {
+	return func(group *Group) {
+		group.observer = append(group.observer, observer)
+	}
+}
+
+// WithOnError adds a callback that is passed every OpError as soon as it is collected,
+// so a long-running Group can report failures before Wait returns. This option can be
+// passed multiple times to add multiple callbacks, which are called in the order they
+// were added.
+//
+// Callbacks are called one at a time from the Group's error collection routine, after
+// the Group's AbortPolicy has been consulted about the error. The next error is not
+// collected until every callback returns, so callbacks should not block, and must not
+// call Wait or WaitContext.
+//
+// Streaming does not remove errors from the Group: every error passed to the callbacks
+// is also included in the GroupErrors returned by Wait, unless it is dropped by the
+// RetentionPolicy set with WithRetention. Retained errors keep the CollectionIndex
+// they were streamed with.
+func WithOnError(callback func(err OpError)) GroupOption {
+	return func(group *Group) {
+		group.onError = append(group.onError, callback)
+	}
+}
@@ -3,6 +3,9 @@ package pears
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 )
 
 // GroupMatchMode determines how GroupErrors should unwrap.
@@ -10,30 +13,88 @@ type GroupMatchMode int
 
 const (
 	// GroupMatchNone tells GroupErrors to match errors.Is or errors.As to not match
-	// on any error. errors.As will still match on a OpError.
+	// on any contained error. errors.As will still match on the GroupErrors itself.
 	//
 	// GroupErrors.Unwrap will return nil in this mode.
 	GroupMatchNone GroupMatchMode = iota
 	// GroupMatchAny tells GroupErrors to match errors.Is or errors.As on any contained
-	// error. GroupErrors.Unwrap will return the first error in this mode.
+	// error. GroupErrors.Unwrap will return every error in this mode, so the standard
+	// library walks every child.
 	GroupMatchAny
 	// GroupMatchFirst tells GroupErrors to unwrap to the first returned error.
 	GroupMatchFirst
+	// GroupMatchRootCauses tells GroupErrors to match errors.Is or errors.As on any
+	// contained error that is not an OpError marked as Fallout. GroupErrors.Unwrap will
+	// return every root cause in this mode.
+	GroupMatchRootCauses
 )
 
 // OpError is a single error returned by a batch operation.
 type OpError struct {
 	// OpName is the name of the operation this error occurred on.
 	OpName string
-	// Err is the error returned by the operation.
+	// Err is the error returned by the operation. If the operation was retried, this is
+	// the error returned by the final attempt.
 	Err error
+	// Attempts is the number of times the operation was attempted.
+	Attempts int
+	// attemptErrs holds the error returned by every attempt, in order. It is kept
+	// behind a pointer so OpError stays comparable.
+	attemptErrs *[]error
+	// TimedOut is true if the operation's final attempt exceeded a timeout or deadline
+	// set by OpWithTimeout or OpWithDeadline.
+	TimedOut bool
+	// Fallout is true if the operation failed as a consequence of it's Group aborting
+	// because of another operation's error, rather than being a root cause. Only
+	// context.Canceled and ErrOpNotStarted errors returned after the Group aborted are
+	// marked as Fallout.
+	Fallout bool
+	// Started is when the operation's first attempt started. Zero if the operation
+	// never started.
+	Started time.Time
+	// Ended is when the operation's final attempt returned.
+	Ended time.Time
+	// LaunchIndex is the order the operation was launched in by it's Group, starting
+	// at 0.
+	LaunchIndex int
+	// CollectionIndex is the order the error was collected in by it's Group, starting
+	// at 0.
+	CollectionIndex int
+}
+
+// Duration returns how long the operation ran for, including any retries. Returns 0
+// if the operation never started.
+func (err OpError) Duration() time.Duration {
+	if err.Started.IsZero() {
+		return 0
+	}
+	return err.Ended.Sub(err.Started)
+}
+
+// AttemptErrs returns the error returned by every attempt, in order. Returns nil if
+// the attempts were not recorded.
+func (err OpError) AttemptErrs() []error {
+	if err.attemptErrs == nil {
+		return nil
+	}
+	return *err.attemptErrs
 }
 
 // Error implements builtins.error.
 func (err OpError) Error() string {
-	return fmt.Sprintf(
-		"error during '%v': %v", err.OpName, err.Err,
-	)
+	return fmt.Sprint(err.prefix(), err.Err)
+}
+
+// prefix returns the text Error prints before Err.
+func (err OpError) prefix() string {
+	prefix := fmt.Sprintf("error during '%v'", err.OpName)
+	if err.TimedOut {
+		prefix += " (timed out)"
+	}
+	if err.Attempts > 1 {
+		prefix += fmt.Sprintf(" after %v attempts", err.Attempts)
+	}
+	return prefix + ": "
 }
 
 // Unwrap implements xerrors.Wrapper.
@@ -43,95 +104,240 @@ func (err OpError) Unwrap() error {
 
 // GroupErrors is a group of errors
 type GroupErrors struct {
-	// MatchMode indicates how Is, As, and Unwrap should behave.
+	// MatchMode indicates which errors Unwrap returns, and so which errors errors.Is
+	// and errors.As will walk.
 	//
-	// GroupMatchNone: Is will always return false, As will only return true if the
-	// target is a *OpError, and Unwrap will return nil if called directly.
+	// GroupMatchNone: Unwrap returns nil, so errors.Is and errors.As never match a
+	// contained error.
 	//
-	// GroupMatchFirst: Is / As will return true if errors.Is/errors.As passes on the
-	// first error in Errs. Unwrap will return the first error in Errs if called
-	// directly.
+	// GroupMatchFirst: Unwrap returns only the first error in Errs.
 	//
-	// GroupMatchAny: Is / As will return true if errors.Is/errors.As passes on ANY error
-	// in Errs. Unwrap will return the first error in Errs if called directly.
+	// GroupMatchAny: Unwrap returns all of Errs.
+	//
+	// GroupMatchRootCauses: Unwrap returns the result of RootCauses.
 	MatchMode GroupMatchMode
 	// Errs are the OpError values we have collected.
 	Errs []error
+	// DisplayLimit caps how many errors in Errs are printed when formatted with %+v.
+	// 0 means there is no limit. A precision passed to the verb, like %+.5v, overrides
+	// this value.
+	DisplayLimit int
+	// Total is the number of errors that were collected, including errors dropped by a
+	// RetentionPolicy. 0 means every collected error is in Errs. Use TotalCount to
+	// account for both cases.
+	Total int
+	// TypeCounts is the number of collected errors of each type, including errors
+	// dropped by a RetentionPolicy. Errors are counted by the type of OpError.Err, as
+	// printed by fmt's %T verb. nil if the errors were not counted.
+	TypeCounts map[string]int
+}
+
+// TotalCount returns the number of errors that were collected, including errors that
+// were not retained.
+func (err GroupErrors) TotalCount() int {
+	if err.Total < len(err.Errs) {
+		return len(err.Errs)
+	}
+	return err.Total
+}
+
+// Dropped returns the number of collected errors that were not retained in Errs.
+func (err GroupErrors) Dropped() int {
+	return err.TotalCount() - len(err.Errs)
 }
 
 // Error implements builtins.error.
 func (err GroupErrors) Error() string {
-	return fmt.Sprintf(
-		"%v errors returned. first: %v", len(err.Errs), err.Errs[0],
-	)
+	return fmt.Sprintf("%v. first: %v", err.countText(), err.Errs[0])
 }
 
-// Unwrap implements xerrors.Wrapper.
-func (err GroupErrors) Unwrap() error {
+// countText describes how many errors we hold, like "10 errors returned", or
+// "1000 errors (10 retained)" if some were not retained.
+func (err GroupErrors) countText() string {
+	if err.Dropped() > 0 {
+		return fmt.Sprintf("%v errors (%v retained)", err.TotalCount(), len(err.Errs))
+	}
+	return fmt.Sprintf("%v errors returned", len(err.Errs))
+}
+
+// Unwrap implements the multi-error form of xerrors.Wrapper used by errors.Is,
+// errors.As and errors.Join. The errors returned depend on MatchMode.
+func (err GroupErrors) Unwrap() []error {
 	// Panic if we do not contain any errors.
 	if len(err.Errs) == 0 {
 		panic("Unwrap() called on pears.GroupErrors value with no inner errors")
 	}
 
-	// Return an error based on the unwrap mode.
+	// Return errors based on the unwrap mode.
 	switch err.MatchMode {
 	case GroupMatchNone:
 		return nil
+	case GroupMatchAny:
+		return err.Errs
+	case GroupMatchRootCauses:
+		return err.RootCauses()
 	default:
-		return err.Errs[0]
+		return err.Errs[:1]
 	}
 }
 
-// Is can be used by errors.Is to match on sub-errors.
-func (err GroupErrors) Is(target error) bool {
-	switch err.MatchMode {
-	case GroupMatchNone:
-		// We are not matching on sub-errors, return false.
-		return false
-	case GroupMatchAny:
-		// Will return true if target passes errors.Is on ANY sub-errors.
-		return err.matchAnyIs(target)
-	default:
-		// Otherwise we can call unwrap to handle the other modes, and compare the
-		// result with errors.Is.
-		compareTo := err.Unwrap()
-		return errors.Is(compareTo, target)
+// Joined returns Errs combined with errors.Join, for use with code that expects the
+// standard library's multi-error type. The returned error always walks every error in
+// Errs, regardless of MatchMode.
+func (err GroupErrors) Joined() error {
+	return errors.Join(err.Errs...)
+}
+
+// GroupErrorsFromJoin converts err into a GroupErrors with mode as it's MatchMode if
+// err implements Unwrap() []error, as values returned by errors.Join do. If err is
+// already a GroupErrors, all of it's errors are kept and only it's MatchMode is
+// changed. Returns false if err does not wrap multiple errors.
+func GroupErrorsFromJoin(err error, mode GroupMatchMode) (GroupErrors, bool) {
+	if groupErr, ok := err.(GroupErrors); ok {
+		groupErr.MatchMode = mode
+		return groupErr, true
 	}
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return GroupErrors{}, false
+	}
+
+	if len(joined.Unwrap()) == 0 {
+		return GroupErrors{}, false
+	}
+
+	// Copy the errors so we do not share a backing array with err.
+	errs := append([]error(nil), joined.Unwrap()...)
+	return GroupErrors{
+		MatchMode: mode,
+		Errs:      errs,
+	}, true
+}
+
+// RootCauses returns every error in Errs that is not an OpError marked as Fallout.
+// Errors that are not an OpError are always considered root causes.
+func (err GroupErrors) RootCauses() []error {
+	return err.filterFallout(false)
 }
 
-// matchAnyIs checks if ANY error in Errs matches target for errors.Is.
-func (err GroupErrors) matchAnyIs(target error) bool {
+// Fallout returns every error in Errs that is an OpError marked as Fallout.
+func (err GroupErrors) Fallout() []error {
+	return err.filterFallout(true)
+}
+
+// filterFallout returns every error in Errs whose fallout status matches fallout.
+func (err GroupErrors) filterFallout(fallout bool) []error {
+	var filtered []error
 	for _, thisErr := range err.Errs {
-		if errors.Is(thisErr, target) {
-			return true
+		opErr := OpError{}
+		isFallout := errors.As(thisErr, &opErr) && opErr.Fallout
+		if isFallout == fallout {
+			filtered = append(filtered, thisErr)
 		}
 	}
-	return false
+	return filtered
 }
 
-// As can be used by errors.As to match on sub-errors.
-func (err GroupErrors) As(target interface{}) bool {
-	switch err.MatchMode {
-	case GroupMatchNone:
-		// We are not matching on sub-errors, return false.
-		return false
-	case GroupMatchAny:
-		// Will return true if target passes errors.Is on ANY sub-errors.
-		return err.matchAnyAs(target)
+// OpErrorSortKey is a field of OpError that GroupErrors.SortedBy can sort on.
+type OpErrorSortKey int
+
+const (
+	// SortByCollectionIndex sorts by OpError.CollectionIndex.
+	SortByCollectionIndex OpErrorSortKey = iota
+	// SortByLaunchIndex sorts by OpError.LaunchIndex.
+	SortByLaunchIndex
+	// SortByStarted sorts by OpError.Started.
+	SortByStarted
+	// SortByEnded sorts by OpError.Ended.
+	SortByEnded
+	// SortByDuration sorts by OpError.Duration.
+	SortByDuration
+)
+
+// less returns true if left should be sorted before right.
+func (key OpErrorSortKey) less(left OpError, right OpError) bool {
+	switch key {
+	case SortByLaunchIndex:
+		return left.LaunchIndex < right.LaunchIndex
+	case SortByStarted:
+		return left.Started.Before(right.Started)
+	case SortByEnded:
+		return left.Ended.Before(right.Ended)
+	case SortByDuration:
+		return left.Duration() < right.Duration()
 	default:
-		// Otherwise we can call unwrap to handle the other modes, and compare the
-		// result with errors.Is.
-		compareTo := err.Unwrap()
-		return errors.As(compareTo, target)
+		return left.CollectionIndex < right.CollectionIndex
 	}
 }
 
-// matchAnyAs checks if ANY error in Errs matches target for errors.As.
-func (err GroupErrors) matchAnyAs(target interface{}) bool {
+// SortedBy returns a copy of err with Errs sorted in ascending order of key. Errs is
+// not modified. The sort is stable, and any errors that are not an OpError are placed
+// after all OpError values in their original order.
+func (err GroupErrors) SortedBy(key OpErrorSortKey) GroupErrors {
+	sorted := make([]error, len(err.Errs))
+	copy(sorted, err.Errs)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		left, leftOk := sorted[i].(OpError)
+		right, rightOk := sorted[j].(OpError)
+		if !leftOk || !rightOk {
+			return leftOk && !rightOk
+		}
+		return key.less(left, right)
+	})
+
+	err.Errs = sorted
+	return err
+}
+
+// Walk calls visit for every leaf error in err's tree. An OpError wrapping a
+// GroupErrors, like those collected from a SubGroup, is a branch of the tree rather
+// than a leaf, as is a GroupErrors directly inside another GroupErrors.
+//
+// path holds the OpName of every OpError leading to the leaf, including the leaf
+// itself if it is an OpError. visit may retain path.
+func (err GroupErrors) Walk(visit func(path []string, leaf error)) {
+	err.walk(nil, visit)
+}
+
+// walk implements Walk for a sub-tree found at path.
+func (err GroupErrors) walk(path []string, visit func(path []string, leaf error)) {
 	for _, thisErr := range err.Errs {
-		if errors.As(thisErr, target) {
-			return true
+		switch typed := thisErr.(type) {
+		case GroupErrors:
+			typed.walk(path, visit)
+			continue
+		case OpError:
+			opPath := append(path[:len(path):len(path)], typed.OpName)
+			if branch, ok := typed.Err.(GroupErrors); ok {
+				branch.walk(opPath, visit)
+			} else {
+				visit(opPath, typed)
+			}
+			continue
 		}
+
+		visit(append([]string(nil), path...), thisErr)
 	}
-	return false
+}
+
+// Flatten returns a copy of err whose Errs holds every leaf error found by Walk. Each
+// leaf OpError has it's OpName replaced by it's full path joined with "/". Total and
+// TypeCounts are cleared, as they only describe err's direct children.
+func (err GroupErrors) Flatten() GroupErrors {
+	var leaves []error
+	err.Walk(func(path []string, leaf error) {
+		if opErr, ok := leaf.(OpError); ok {
+			opErr.OpName = strings.Join(path, "/")
+			leaf = opErr
+		}
+		leaves = append(leaves, leaf)
+	})
+
+	err.Errs = leaves
+	// Our counts describe our direct children, not the leaves.
+	err.Total = 0
+	err.TypeCounts = nil
+	return err
 }
@@ -1,13 +1,16 @@
 package pears_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/peake100/pears-go/pkg/pears"
 	"github.com/stretchr/testify/assert"
 	"io"
 	"net"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestBatchErrors_Is(t *testing.T) {
@@ -140,6 +143,30 @@ func TestBatchErrors_Is(t *testing.T) {
 			Target:     io.EOF,
 			IsExpected: false,
 		},
+		{
+			Name: "RootCauses_HasMatch_2ndOf2",
+			Err: pears.GroupErrors{
+				MatchMode: pears.GroupMatchRootCauses,
+				Errs: []error{
+					pears.OpError{OpName: "fallout", Err: io.ErrClosedPipe, Fallout: true},
+					pears.OpError{OpName: "cause", Err: io.EOF},
+				},
+			},
+			Target:     io.EOF,
+			IsExpected: true,
+		},
+		{
+			Name: "RootCauses_NoMatch_Fallout",
+			Err: pears.GroupErrors{
+				MatchMode: pears.GroupMatchRootCauses,
+				Errs: []error{
+					pears.OpError{OpName: "cause", Err: io.ErrClosedPipe},
+					pears.OpError{OpName: "fallout", Err: io.EOF, Fallout: true},
+				},
+			},
+			Target:     io.EOF,
+			IsExpected: false,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -282,6 +309,34 @@ func TestBatchErrors_As(t *testing.T) {
 			Target:     nil,
 			IsExpected: false,
 		},
+		{
+			Name: "RootCauses_HasMatch_2ndOf2",
+			Err: pears.GroupErrors{
+				MatchMode: pears.GroupMatchRootCauses,
+				Errs: []error{
+					pears.OpError{OpName: "fallout", Err: io.ErrClosedPipe, Fallout: true},
+					net.InvalidAddrError("mock error"),
+				},
+			},
+			Target:     nil,
+			IsExpected: true,
+		},
+		{
+			Name: "RootCauses_NoMatch_Fallout",
+			Err: pears.GroupErrors{
+				MatchMode: pears.GroupMatchRootCauses,
+				Errs: []error{
+					io.ErrClosedPipe,
+					pears.OpError{
+						OpName:  "fallout",
+						Err:     net.InvalidAddrError("mock error"),
+						Fallout: true,
+					},
+				},
+			},
+			Target:     nil,
+			IsExpected: false,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -315,6 +370,10 @@ func TestBatchErrors_As_MatchBatchError(t *testing.T) {
 			Name:      "GroupMatchAny",
 			MatchMode: pears.GroupMatchAny,
 		},
+		{
+			Name:      "GroupMatchRootCauses",
+			MatchMode: pears.GroupMatchRootCauses,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -420,3 +479,315 @@ func TestBatchError(t *testing.T) {
 		assert.ErrorIs(t, err, io.EOF, "error unwraps to io.EOF")
 	})
 }
+
+func TestBatchError_Error_TimedOutAfterAttempts(t *testing.T) {
+	err := pears.OpError{
+		OpName:   "slow",
+		Err:      context.DeadlineExceeded,
+		Attempts: 3,
+		TimedOut: true,
+	}
+
+	assert.EqualError(
+		t, err, "error during 'slow' (timed out) after 3 attempts: context deadline exceeded",
+	)
+}
+
+func TestBatchError_Comparable(t *testing.T) {
+	assert := assert.New(t)
+
+	manager := pears.NewGroup(context.Background())
+	manager.GoNamed("read file", func(ctx context.Context) error {
+		return io.EOF
+	})
+
+	opErr := pears.OpError{}
+	if !assert.ErrorAs(manager.Wait(), &opErr) {
+		t.FailNow()
+	}
+	assert.Equal([]error{io.EOF}, opErr.AttemptErrs(), "attempt errors")
+
+	// Recorded attempt errors must not stop OpError being used with == or as a map
+	// key.
+	assert.NotPanics(func() {
+		seen := map[error]bool{opErr: true}
+		assert.True(seen[opErr], "found by key")
+	}, "OpError is comparable")
+}
+
+func TestBatchErrors_RootCauses(t *testing.T) {
+	assert := assert.New(t)
+
+	cause := pears.OpError{OpName: "cause", Err: io.EOF}
+	fallout1 := pears.OpError{OpName: "fallout1", Err: context.Canceled, Fallout: true}
+	fallout2 := pears.OpError{OpName: "fallout2", Err: context.Canceled, Fallout: true}
+
+	err := pears.GroupErrors{
+		MatchMode: pears.GroupMatchRootCauses,
+		Errs: []error{
+			fallout1,
+			cause,
+			fallout2,
+			io.ErrUnexpectedEOF,
+		},
+	}
+
+	assert.Equal([]error{cause, io.ErrUnexpectedEOF}, err.RootCauses(), "root causes")
+	assert.Equal([]error{fallout1, fallout2}, err.Fallout(), "fallout")
+	assert.Equal([]error{cause, io.ErrUnexpectedEOF}, err.Unwrap(), "unwraps to root causes")
+}
+
+func TestBatchErrors_SortedBy(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Each error is first in a different sort order.
+	errA := pears.OpError{
+		OpName:          "a",
+		Started:         start.Add(2 * time.Second),
+		Ended:           start.Add(3 * time.Second),
+		LaunchIndex:     0,
+		CollectionIndex: 2,
+	}
+	errB := pears.OpError{
+		OpName:          "b",
+		Started:         start,
+		Ended:           start.Add(5 * time.Second),
+		LaunchIndex:     2,
+		CollectionIndex: 1,
+	}
+	errC := pears.OpError{
+		OpName:          "c",
+		Started:         start.Add(time.Second),
+		Ended:           start.Add(2 * time.Second),
+		LaunchIndex:     1,
+		CollectionIndex: 0,
+	}
+
+	err := pears.GroupErrors{
+		MatchMode: pears.GroupMatchFirst,
+		Errs:      []error{io.EOF, errA, errB, errC},
+	}
+
+	testCases := []struct {
+		Name     string
+		Key      pears.OpErrorSortKey
+		Expected []error
+	}{
+		{
+			Name:     "CollectionIndex",
+			Key:      pears.SortByCollectionIndex,
+			Expected: []error{errC, errB, errA, io.EOF},
+		},
+		{
+			Name:     "LaunchIndex",
+			Key:      pears.SortByLaunchIndex,
+			Expected: []error{errA, errC, errB, io.EOF},
+		},
+		{
+			Name:     "Started",
+			Key:      pears.SortByStarted,
+			Expected: []error{errB, errC, errA, io.EOF},
+		},
+		{
+			Name:     "Ended",
+			Key:      pears.SortByEnded,
+			Expected: []error{errC, errA, errB, io.EOF},
+		},
+		{
+			Name:     "Duration",
+			Key:      pears.SortByDuration,
+			Expected: []error{errA, errC, errB, io.EOF},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			sorted := err.SortedBy(tc.Key)
+			assert.Equal(t, tc.Expected, sorted.Errs, "errors sorted")
+			assert.Equal(t, io.EOF, err.Errs[0], "original not modified")
+		})
+	}
+}
+
+func TestBatchErrors_StdlibMultiError(t *testing.T) {
+	errs := []error{
+		pears.OpError{OpName: "fallout", Err: io.ErrClosedPipe, Fallout: true},
+		pears.OpError{OpName: "first cause", Err: io.EOF},
+		pears.OpError{OpName: "second cause", Err: net.InvalidAddrError("mock error")},
+	}
+
+	testCases := []struct {
+		// The match mode to test.
+		MatchMode pears.GroupMatchMode
+		// Whether errors.Is is expected to pass for each of io.ErrClosedPipe, io.EOF.
+		IsClosedPipe bool
+		IsEOF        bool
+		// Whether errors.As is expected to pass for net.InvalidAddrError.
+		AsInvalidAddr bool
+		// The number of children the stdlib should see through Unwrap() []error.
+		Children int
+	}{
+		{
+			MatchMode:     pears.GroupMatchNone,
+			IsClosedPipe:  false,
+			IsEOF:         false,
+			AsInvalidAddr: false,
+			Children:      0,
+		},
+		{
+			MatchMode:     pears.GroupMatchFirst,
+			IsClosedPipe:  true,
+			IsEOF:         false,
+			AsInvalidAddr: false,
+			Children:      1,
+		},
+		{
+			MatchMode:     pears.GroupMatchAny,
+			IsClosedPipe:  true,
+			IsEOF:         true,
+			AsInvalidAddr: true,
+			Children:      3,
+		},
+		{
+			MatchMode:     pears.GroupMatchRootCauses,
+			IsClosedPipe:  false,
+			IsEOF:         true,
+			AsInvalidAddr: true,
+			Children:      2,
+		},
+	}
+
+	for _, tc := range testCases {
+		groupErr := pears.GroupErrors{MatchMode: tc.MatchMode, Errs: errs}
+
+		// Each way of holding our GroupErrors should behave the same way.
+		wrappers := map[string]error{
+			"raw":     groupErr,
+			"wrapped": fmt.Errorf("wrapped: %w", groupErr),
+			"joined":  errors.Join(io.ErrNoProgress, groupErr),
+		}
+
+		for name, err := range wrappers {
+			t.Run(fmt.Sprint("mode_", tc.MatchMode, "/", name), func(t *testing.T) {
+				assert := assert.New(t)
+				assert.Equal(tc.IsClosedPipe, errors.Is(err, io.ErrClosedPipe), "Is ClosedPipe")
+				assert.Equal(tc.IsEOF, errors.Is(err, io.EOF), "Is EOF")
+
+				var target net.InvalidAddrError
+				assert.Equal(tc.AsInvalidAddr, errors.As(err, &target), "As InvalidAddr")
+
+				extracted := pears.GroupErrors{}
+				assert.ErrorAs(err, &extracted, "As GroupErrors")
+			})
+		}
+
+		t.Run(fmt.Sprint("mode_", tc.MatchMode, "/Unwrap"), func(t *testing.T) {
+			var multi interface{ Unwrap() []error } = groupErr
+			assert.Len(t, multi.Unwrap(), tc.Children, "children")
+		})
+	}
+}
+
+func TestBatchErrors_Joined(t *testing.T) {
+	assert := assert.New(t)
+
+	groupErr := pears.GroupErrors{
+		MatchMode: pears.GroupMatchNone,
+		Errs:      []error{io.EOF, io.ErrClosedPipe},
+	}
+
+	joined := groupErr.Joined()
+	assert.ErrorIs(joined, io.EOF, "joined walks first error")
+	assert.ErrorIs(joined, io.ErrClosedPipe, "joined walks second error")
+
+	converted, ok := pears.GroupErrorsFromJoin(joined, pears.GroupMatchFirst)
+	if !assert.True(ok, "converted from join") {
+		t.FailNow()
+	}
+	assert.Equal(pears.GroupMatchFirst, converted.MatchMode, "match mode set")
+	assert.Equal(groupErr.Errs, converted.Errs, "errors preserved")
+
+	converted, ok = pears.GroupErrorsFromJoin(groupErr, pears.GroupMatchAny)
+	if assert.True(ok, "converted from GroupErrors") {
+		assert.Equal(pears.GroupMatchAny, converted.MatchMode, "match mode set")
+		assert.Equal(groupErr.Errs, converted.Errs, "errors preserved")
+	}
+
+	_, ok = pears.GroupErrorsFromJoin(io.EOF, pears.GroupMatchAny)
+	assert.False(ok, "single error not converted")
+}
+
+func TestBatchErrors_Walk(t *testing.T) {
+	assert := assert.New(t)
+
+	err := pears.GroupErrors{
+		Errs: []error{
+			pears.OpError{OpName: "a", Err: io.EOF},
+			pears.OpError{
+				OpName: "b",
+				Err: pears.GroupErrors{
+					Errs: []error{
+						pears.OpError{OpName: "c", Err: io.ErrUnexpectedEOF},
+						io.ErrClosedPipe,
+						pears.GroupErrors{
+							Errs: []error{pears.OpError{OpName: "d", Err: io.ErrNoProgress}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var paths []string
+	var leaves []error
+	err.Walk(func(path []string, leaf error) {
+		paths = append(paths, strings.Join(path, "/"))
+		leaves = append(leaves, leaf)
+	})
+
+	assert.Equal([]string{"a", "b/c", "b", "b/d"}, paths, "paths")
+	if assert.Len(leaves, 4, "4 leaves") {
+		assert.ErrorIs(leaves[0], io.EOF)
+		assert.ErrorIs(leaves[1], io.ErrUnexpectedEOF)
+		assert.ErrorIs(leaves[2], io.ErrClosedPipe)
+		assert.ErrorIs(leaves[3], io.ErrNoProgress)
+	}
+
+	flat := err.Flatten()
+	if assert.Len(flat.Errs, 4, "4 flattened errors") {
+		assert.EqualError(flat.Errs[1], "error during 'b/c': unexpected EOF")
+		assert.Equal(io.ErrClosedPipe, flat.Errs[2], "non-OpError leaf kept as-is")
+		assert.EqualError(flat.Errs[3], "error during 'b/d': multiple Read calls return no data or error")
+	}
+}
+
+// countingErr counts how many times errors.Is compares it to a target.
+type countingErr struct {
+	calls *int
+}
+
+// Error implements builtins.error.
+func (err countingErr) Error() string {
+	return "counting"
+}
+
+// Is implements the interface used by errors.Is.
+func (err countingErr) Is(target error) bool {
+	*err.calls++
+	return false
+}
+
+func TestGroupErrors_Is_NestedVisitsOnce(t *testing.T) {
+	calls := 0
+
+	var err error = countingErr{calls: &calls}
+	for i := 0; i < 20; i++ {
+		err = pears.GroupErrors{
+			MatchMode: pears.GroupMatchAny,
+			Errs:      []error{err, io.ErrUnexpectedEOF},
+		}
+	}
+
+	assert.False(t, errors.Is(err, io.EOF), "no match")
+	assert.Equal(t, 1, calls, "leaf compared once")
+}
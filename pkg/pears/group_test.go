@@ -7,7 +7,9 @@ import (
 	"github.com/peake100/pears-go/pkg/pears"
 	"github.com/stretchr/testify/assert"
 	"io"
+	"runtime"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -121,6 +123,35 @@ func TestRoutineManager_AbortOnError(t *testing.T) {
 	}
 
 	assert.Equal(causingErr.OpName, "failOp", "first error is OpError from 'failOp' routine")
+
+	// Only the io.EOF is a root cause. The cancellations are fallout from the abort.
+	assert.False(causingErr.Fallout, "causing error is not fallout")
+	assert.Equal([]error{batchErrs.Errs[0]}, batchErrs.RootCauses(), "1 root cause")
+	assert.Len(batchErrs.Fallout(), 10, "10 fallout errors")
+}
+
+func TestRoutineManager_ParentCancelled_NotFallout(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	manager := pears.NewGroup(ctx)
+
+	manager.GoNamed("cancelled", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	cancel()
+	err := manager.Wait()
+
+	batchErrs := pears.GroupErrors{}
+	if !assert.ErrorAs(err, &batchErrs) {
+		t.FailNow()
+	}
+
+	// The group did not abort itself, so the cancellation is a root cause.
+	assert.Len(batchErrs.RootCauses(), 1, "cancellation is a root cause")
+	assert.Empty(batchErrs.Fallout(), "no fallout")
 }
 
 func TestRoutineManager_DoNotAbortOnError(t *testing.T) {
@@ -242,3 +273,651 @@ func TestRoutineManager_GroupMatchAll(t *testing.T) {
 	assert.ErrorIs(err, io.EOF)
 	assert.ErrorIs(err, io.ErrClosedPipe)
 }
+
+func TestRoutineManager_MaxConcurrency(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	manager := pears.NewGroup(ctx, pears.WithMaxConcurrency(3))
+
+	// Track how many ops are running at once, and the most we ever saw.
+	var running int32
+	var maxRunning int32
+
+	for i := 0; i < 20; i++ {
+		manager.GoNamed(fmt.Sprint("op", i), func(ctx context.Context) error {
+			current := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+
+			for {
+				seen := atomic.LoadInt32(&maxRunning)
+				if current <= seen || atomic.CompareAndSwapInt32(&maxRunning, seen, current) {
+					break
+				}
+			}
+
+			time.Sleep(5 * time.Millisecond)
+			return nil
+		})
+	}
+
+	err := manager.Wait()
+	assert.NoError(err, "no errors running routines")
+	assert.Equal(int32(3), maxRunning, "at most 3 ops ran at once")
+}
+
+func TestRoutineManager_MaxConcurrency_QueuedNotStartedOnCancel(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	manager := pears.NewGroup(ctx, pears.WithMaxConcurrency(1))
+
+	release := make(chan struct{})
+	manager.GoNamed("blocking", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+
+	// These ops will be queued behind the blocking op, and should never run.
+	queuedRan := int32(0)
+	for i := 0; i < 5; i++ {
+		manager.GoNamed(fmt.Sprint("queued", i), func(ctx context.Context) error {
+			atomic.AddInt32(&queuedRan, 1)
+			return nil
+		})
+	}
+
+	cancel()
+	close(release)
+
+	err := manager.Wait()
+
+	batchErrs := pears.GroupErrors{}
+	if !assert.ErrorAs(err, &batchErrs) {
+		t.FailNow()
+	}
+
+	assert.Equal(int32(0), queuedRan, "queued ops were not run")
+	if !assert.Len(batchErrs.Errs, 5, "one error per queued op") {
+		t.FailNow()
+	}
+
+	for i, thisErr := range batchErrs.Errs {
+		assert.ErrorIs(thisErr, pears.ErrOpNotStarted, "error is ErrOpNotStarted")
+		assert.NotErrorIs(thisErr, context.Canceled, "error is not context.Canceled")
+
+		opErr := pears.OpError{}
+		if assert.ErrorAs(thisErr, &opErr) {
+			assert.Equal(fmt.Sprint("queued", i), opErr.OpName, "queued ops are reported in order")
+		}
+	}
+}
+
+func TestRoutineManager_PanicRecovered(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	manager := pears.NewGroup(ctx)
+
+	// This op will only return once the panic below aborts the group.
+	manager.GoNamed("waiter", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	manager.GoNamed("panics", func(ctx context.Context) error {
+		panic(io.EOF)
+	})
+
+	err := manager.Wait()
+
+	batchErrs := pears.GroupErrors{}
+	if !assert.ErrorAs(err, &batchErrs) {
+		t.FailNow()
+	}
+
+	if !assert.Len(batchErrs.Errs, 2, "both ops returned errors") {
+		t.FailNow()
+	}
+
+	causingErr := pears.OpError{}
+	if !assert.ErrorAs(batchErrs, &causingErr) {
+		t.FailNow()
+	}
+	assert.Equal("panics", causingErr.OpName, "first error is from panicking op")
+
+	panicErr := pears.PanicError{}
+	if !assert.ErrorAs(causingErr, &panicErr, "error is PanicError") {
+		t.FailNow()
+	}
+	assert.ErrorIs(panicErr, io.EOF, "panic error wraps io.EOF")
+	assert.NotZero(panicErr.StackTrace, "stack trace not empty")
+
+	assert.ErrorIs(batchErrs.Errs[1], context.Canceled, "waiter op was aborted")
+}
+
+func TestRoutineManager_Goexit(t *testing.T) {
+	for _, recoverPanics := range []bool{true, false} {
+		t.Run(fmt.Sprintf("RecoverPanics_%v", recoverPanics), func(t *testing.T) {
+			assert := assert.New(t)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			// Limit concurrency so the second op is queued behind the exiting op, and
+			// must be started once the exiting op's routine is gone.
+			manager := pears.NewGroup(
+				context.Background(),
+				pears.WithMaxConcurrency(1),
+				pears.WithAbortOnError(false),
+				pears.WithPanicRecovery(recoverPanics),
+			)
+
+			manager.GoNamed("exits", func(ctx context.Context) error {
+				runtime.Goexit()
+				return nil
+			})
+
+			queuedRan := false
+			manager.GoNamed("queued", func(ctx context.Context) error {
+				queuedRan = true
+				return nil
+			})
+
+			// Use WaitContext so a leaked slot fails the test rather than hanging it.
+			err := manager.WaitContext(ctx)
+
+			opErr := pears.OpError{}
+			if !assert.ErrorAs(err, &opErr) {
+				t.FailNow()
+			}
+			assert.Equal("exits", opErr.OpName, "error is from exiting op")
+			assert.Equal(1, opErr.Attempts, "attempts")
+
+			goexitErr := pears.GoexitError{}
+			if !assert.ErrorAs(opErr, &goexitErr, "error is GoexitError") {
+				t.FailNow()
+			}
+			assert.Contains(goexitErr.StackTrace, "runtime.Goexit", "stack trace")
+
+			assert.True(queuedRan, "queued op was started")
+			assert.Equal(
+				pears.GroupStats{Launched: 2, Succeeded: 1, Failed: 1},
+				manager.Stats(),
+				"stats",
+			)
+		})
+	}
+}
+
+func TestRoutineManager_OpTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	manager := pears.NewGroup(ctx)
+
+	// This op will be cancelled by the timeout of the op below aborting the group.
+	manager.GoNamed("sibling", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	manager.GoNamed(
+		"slow",
+		func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+		pears.OpWithTimeout(10*time.Millisecond),
+	)
+
+	err := manager.Wait()
+
+	batchErrs := pears.GroupErrors{}
+	if !assert.ErrorAs(err, &batchErrs) {
+		t.FailNow()
+	}
+	if !assert.Len(batchErrs.Errs, 2, "both ops returned errors") {
+		t.FailNow()
+	}
+
+	timedOut := pears.OpError{}
+	if assert.ErrorAs(batchErrs.Errs[0], &timedOut) {
+		assert.Equal("slow", timedOut.OpName, "slow op error is first")
+		assert.True(timedOut.TimedOut, "slow op timed out")
+		assert.ErrorIs(timedOut, context.DeadlineExceeded)
+		assert.EqualError(timedOut, "error during 'slow' (timed out): context deadline exceeded")
+	}
+
+	sibling := pears.OpError{}
+	if assert.ErrorAs(batchErrs.Errs[1], &sibling) {
+		assert.Equal("sibling", sibling.OpName, "sibling op error is second")
+		assert.False(sibling.TimedOut, "sibling op did not time out")
+		assert.ErrorIs(sibling, context.Canceled)
+	}
+}
+
+func TestRoutineManager_OpDeadline_GroupCancelled(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	manager := pears.NewGroup(ctx)
+
+	manager.GoNamed(
+		"cancelled",
+		func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+		pears.OpWithDeadline(time.Now().Add(time.Hour)),
+	)
+
+	cancel()
+	err := manager.Wait()
+
+	opErr := pears.OpError{}
+	if assert.ErrorAs(err, &opErr) {
+		assert.False(opErr.TimedOut, "op cancelled by group did not time out")
+		assert.ErrorIs(opErr, context.Canceled)
+	}
+}
+
+func TestRoutineManager_OpErrorMetadata(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	manager := pears.NewGroup(ctx, pears.WithAbortOnError(false))
+
+	// Capture the time before launching, as ops may start as soon as they are launched.
+	before := time.Now()
+
+	// Each op sleeps for less time than the one before it, so errors are collected in
+	// the reverse order they were launched in.
+	for i := 0; i < 3; i++ {
+		sleep := time.Duration(3-i) * 20 * time.Millisecond
+		manager.GoNamed(fmt.Sprint("op", i), func(ctx context.Context) error {
+			time.Sleep(sleep)
+			return io.EOF
+		})
+	}
+
+	err := manager.Wait()
+
+	batchErrs := pears.GroupErrors{}
+	if !assert.ErrorAs(err, &batchErrs) {
+		t.FailNow()
+	}
+	if !assert.Len(batchErrs.Errs, 3, "all ops failed") {
+		t.FailNow()
+	}
+
+	for i, thisErr := range batchErrs.Errs {
+		opErr := thisErr.(pears.OpError)
+		assert.Equal(i, opErr.CollectionIndex, "collection index")
+		assert.Equal(fmt.Sprint("op", opErr.LaunchIndex), opErr.OpName, "launch index")
+		assert.False(opErr.Started.Before(before), "started after launch")
+		assert.True(opErr.Ended.After(opErr.Started), "ended after started")
+		assert.Equal(opErr.Ended.Sub(opErr.Started), opErr.Duration(), "duration")
+		assert.GreaterOrEqual(
+			opErr.Duration(),
+			time.Duration(3-opErr.LaunchIndex)*20*time.Millisecond,
+			"duration includes op run time",
+		)
+	}
+
+	assert.Equal("op2", batchErrs.Errs[0].(pears.OpError).OpName, "fastest op first")
+
+	byLaunch := batchErrs.SortedBy(pears.SortByLaunchIndex)
+	for i, thisErr := range byLaunch.Errs {
+		assert.Equal(i, thisErr.(pears.OpError).LaunchIndex, "sorted by launch index")
+	}
+}
+
+func TestRoutineManager_SubGroup(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	manager := pears.NewGroup(ctx, pears.WithAbortOnError(false))
+
+	// This sibling of the sub-group should not be cancelled by the sub-group's abort.
+	siblingDone := make(chan struct{})
+	manager.GoNamed("sibling", func(ctx context.Context) error {
+		defer close(siblingDone)
+		timer := time.NewTimer(50 * time.Millisecond)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return nil
+		}
+	})
+
+	shard := manager.SubGroup("shard")
+	shard.GoNamed("file1", func(ctx context.Context) error {
+		return io.EOF
+	})
+	shard.GoNamed("file2", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	shardErr := shard.Wait()
+	assert.Error(shardErr, "sub-group returns it's own error")
+	<-siblingDone
+
+	err := manager.Wait()
+
+	batchErrs := pears.GroupErrors{}
+	if !assert.ErrorAs(err, &batchErrs) {
+		t.FailNow()
+	}
+	if !assert.Len(batchErrs.Errs, 1, "only the sub-group failed") {
+		t.FailNow()
+	}
+
+	opErr := pears.OpError{}
+	if !assert.ErrorAs(batchErrs.Errs[0], &opErr) {
+		t.FailNow()
+	}
+	assert.Equal("shard", opErr.OpName, "sub-group reported under it's name")
+	assert.Equal(shardErr, opErr.Err, "sub-group error reported")
+	assert.ErrorIs(err, io.EOF, "nested error matches")
+
+	flat := batchErrs.Flatten()
+	if assert.Len(flat.Errs, 2, "2 leaf errors") {
+		assert.Equal("shard/file1", flat.Errs[0].(pears.OpError).OpName)
+		assert.Equal("shard/file2", flat.Errs[1].(pears.OpError).OpName)
+	}
+}
+
+func TestRoutineManager_SubGroup_ParentAbortCancelsChild(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	manager := pears.NewGroup(ctx)
+	shard := manager.SubGroup("shard")
+
+	shard.GoNamed("file", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	manager.GoNamed("fails", func(ctx context.Context) error {
+		return io.EOF
+	})
+
+	// Wait on the sub-group from an op, the way nested pipelines would.
+	manager.GoNamed("waits on shard", func(ctx context.Context) error {
+		return shard.Wait()
+	})
+
+	err := manager.Wait()
+
+	batchErrs := pears.GroupErrors{}
+	if !assert.ErrorAs(err, &batchErrs) {
+		t.FailNow()
+	}
+
+	assert.Len(batchErrs.RootCauses(), 1, "only the failing op is a root cause")
+	assert.ErrorIs(batchErrs.Errs[0], io.EOF, "first error is io.EOF")
+}
+
+func TestRoutineManager_SubGroup_ParentAbortIsFallout(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	manager := pears.NewGroup(ctx)
+	shard := manager.SubGroup("shard")
+
+	fileStarted := make(chan struct{})
+	shard.GoNamed("file", func(ctx context.Context) error {
+		close(fileStarted)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	// Fail a sibling of the sub-group once the sub-group's op is running.
+	manager.GoNamed("fails", func(ctx context.Context) error {
+		<-fileStarted
+		return io.EOF
+	})
+
+	shardErr := shard.Wait()
+	err := manager.Wait()
+
+	opErr := pears.OpError{}
+	if assert.ErrorAs(shardErr, &opErr, "shard error is OpError") {
+		assert.Equal("file", opErr.OpName, "shard op name")
+		assert.True(opErr.Fallout, "parent abort is fallout in child")
+	}
+
+	batchErrs := pears.GroupErrors{}
+	if !assert.ErrorAs(err, &batchErrs) {
+		t.FailNow()
+	}
+
+	rootCauses := batchErrs.Flatten().RootCauses()
+	if assert.Len(rootCauses, 1, "only the failing sibling is a root cause") {
+		assert.Equal("fails", rootCauses[0].(pears.OpError).OpName, "root cause op")
+	}
+}
+
+func TestRoutineManager_SubGroup_PanicsAfterWait(t *testing.T) {
+	manager := pears.NewGroup(context.Background())
+	manager.Wait()
+
+	assert.Panics(t, func() {
+		manager.SubGroup("panics")
+	}, "panic on SubGroup after Wait()")
+}
+
+func TestRoutineManager_WaitContext_Abandoned(t *testing.T) {
+	assert := assert.New(t)
+
+	release := make(chan struct{})
+	started := new(sync.WaitGroup)
+	started.Add(2)
+
+	group := pears.NewGroup(context.Background())
+	group.GoNamed("ignores ctx", func(ctx context.Context) error {
+		started.Done()
+		<-release
+		return io.EOF
+	})
+	group.GoNamed("finishes", func(ctx context.Context) error {
+		return nil
+	})
+	// This op returns context.Canceled because the wait was abandoned, so it should be
+	// marked as fallout.
+	group.GoNamed("cancelled", func(ctx context.Context) error {
+		started.Done()
+		<-ctx.Done()
+		<-release
+		return ctx.Err()
+	})
+	started.Wait()
+
+	waitCtx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := group.WaitContext(waitCtx)
+
+	abandoned := pears.WaitAbandonedError{}
+	if !assert.ErrorAs(err, &abandoned, "error is WaitAbandonedError") {
+		t.FailNow()
+	}
+	assert.ErrorIs(err, context.DeadlineExceeded, "error is DeadlineExceeded")
+	assert.Equal([]string{"ignores ctx", "cancelled"}, abandoned.Running, "running ops")
+	assert.EqualError(
+		err,
+		"wait abandoned with 2 ops still running ['ignores ctx', 'cancelled']: context"+
+			" deadline exceeded",
+	)
+
+	select {
+	case <-group.Done():
+		t.Fatal("Done closed before ops finished")
+	default:
+	}
+	assert.NoError(group.Err(), "no result before done")
+
+	// Releasing the op should finish the group in the background.
+	close(release)
+	select {
+	case <-group.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Done not closed after ops finished")
+	}
+
+	// Both ops return once released, so either may be collected first.
+	groupErrs := pears.GroupErrors{}
+	if !assert.ErrorAs(group.Err(), &groupErrs, "final result available") {
+		t.FailNow()
+	}
+	for _, opErr := range groupErrs.Errs {
+		cancelled := opErr.(pears.OpError).OpName == "cancelled"
+		assert.Equal(cancelled, opErr.(pears.OpError).Fallout, "fallout of %v", opErr)
+	}
+
+	rootCauses := groupErrs.RootCauses()
+	if !assert.Len(rootCauses, 1, "root causes") {
+		t.FailNow()
+	}
+	assert.ErrorIs(rootCauses[0], io.EOF, "io.EOF is the root cause")
+}
+
+func TestRoutineManager_WaitContext_Completes(t *testing.T) {
+	assert := assert.New(t)
+
+	group := pears.NewGroup(context.Background())
+	group.GoNamed("fails", func(ctx context.Context) error {
+		return io.EOF
+	})
+
+	err := group.WaitContext(context.Background())
+	assert.ErrorIs(err, io.EOF, "group result returned")
+	assert.Equal(err, group.Err(), "Err matches result")
+
+	select {
+	case <-group.Done():
+	default:
+		t.Fatal("Done not closed")
+	}
+}
+
+func TestRoutineManager_Done_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	group := pears.NewGroup(context.Background())
+	group.Go(func(ctx context.Context) error {
+		return nil
+	})
+
+	go func() {
+		_ = group.Wait()
+	}()
+
+	select {
+	case <-group.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Done not closed")
+	}
+	assert.NoError(group.Err(), "no error")
+}
+
+func TestRoutineManager_WaitContext_PanicOnSecondCall(t *testing.T) {
+	group := pears.NewGroup(context.Background())
+	_ = group.WaitContext(context.Background())
+
+	assert.Panics(t, func() {
+		_ = group.Wait()
+	}, "Wait after WaitContext panics")
+	assert.Panics(t, func() {
+		_ = group.WaitContext(context.Background())
+	}, "second WaitContext panics")
+}
+
+func TestRoutineManager_WithOnError(t *testing.T) {
+	assert := assert.New(t)
+
+	release := make(chan struct{})
+	streamed := make(chan pears.OpError, 3)
+
+	group := pears.NewGroup(
+		context.Background(),
+		pears.WithAbortOnError(false),
+		pears.WithErrMode(pears.GroupMatchAny),
+		pears.WithOnError(func(err pears.OpError) {
+			streamed <- err
+		}),
+	)
+
+	group.GoNamed("fails early", func(ctx context.Context) error {
+		return io.EOF
+	})
+	group.GoNamed("fails late", func(ctx context.Context) error {
+		<-release
+		return io.ErrUnexpectedEOF
+	})
+
+	// The first error should be streamed before the second op has returned.
+	select {
+	case err := <-streamed:
+		assert.Equal("fails early", err.OpName, "first streamed op")
+		assert.ErrorIs(err, io.EOF, "first streamed error")
+		assert.Equal(0, err.CollectionIndex, "collection index")
+	case <-time.After(5 * time.Second):
+		t.Fatal("error not streamed")
+	}
+
+	close(release)
+	err := group.Wait()
+
+	late := <-streamed
+	assert.Equal("fails late", late.OpName, "second streamed op")
+
+	// Streamed errors are still returned by Wait.
+	groupErr := pears.GroupErrors{}
+	if !assert.ErrorAs(err, &groupErr, "error is GroupErrors") {
+		t.FailNow()
+	}
+	assert.Len(groupErr.Errs, 2, "all errors returned by Wait")
+	assert.Equal(late, groupErr.Errs[1], "streamed error matches collected error")
+}
+
+func TestRoutineManager_WithOnError_Multiple(t *testing.T) {
+	var calls []string
+
+	group := pears.NewGroup(
+		context.Background(),
+		pears.WithOnError(func(err pears.OpError) {
+			calls = append(calls, "first")
+		}),
+		pears.WithOnError(func(err pears.OpError) {
+			calls = append(calls, "second")
+		}),
+	)
+	group.Go(func(ctx context.Context) error {
+		return io.EOF
+	})
+	_ = group.Wait()
+
+	assert.Equal(t, []string{"first", "second"}, calls, "callbacks called in order")
+}
@@ -0,0 +1,381 @@
+package pears
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Kind values used as the "kind" discriminator in the JSON encoding of errors.
+const (
+	jsonKindOpError     = "op_error"
+	jsonKindGroupErrors = "group_errors"
+	jsonKindPanicError  = "panic_error"
+	jsonKindGoexitError = "goexit_error"
+	jsonKindOpaque      = "opaque"
+)
+
+// OpaqueError stands in for an error whose concrete type could not be restored by
+// UnmarshalError. It keeps the original error's message and type name.
+type OpaqueError struct {
+	// TypeName is the original error's type, as printed by fmt's %T verb.
+	TypeName string
+	// Message is the original error's Error() text.
+	Message string
+}
+
+// Error implements builtins.error.
+func (err OpaqueError) Error() string {
+	return err.Message
+}
+
+// jsonError is the JSON schema shared by every error type in this package. Fields
+// that do not apply to an error's kind are left out.
+type jsonError struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+	Type    string `json:"type,omitempty"`
+
+	// OpError fields.
+	OpName          string       `json:"op_name,omitempty"`
+	Err             *jsonError   `json:"error,omitempty"`
+	Attempts        int          `json:"attempts,omitempty"`
+	AttemptErrs     []*jsonError `json:"attempt_errors,omitempty"`
+	TimedOut        bool         `json:"timed_out,omitempty"`
+	Fallout         bool         `json:"fallout,omitempty"`
+	Started         *time.Time   `json:"started,omitempty"`
+	Ended           *time.Time   `json:"ended,omitempty"`
+	LaunchIndex     int          `json:"launch_index,omitempty"`
+	CollectionIndex int          `json:"collection_index,omitempty"`
+
+	// GroupErrors fields.
+	MatchMode    string         `json:"match_mode,omitempty"`
+	DisplayLimit int            `json:"display_limit,omitempty"`
+	Errs         []*jsonError   `json:"errors,omitempty"`
+	Total        int            `json:"total,omitempty"`
+	TypeCounts   map[string]int `json:"type_counts,omitempty"`
+
+	// PanicError and GoexitError fields.
+	Recovered    json.RawMessage `json:"recovered,omitempty"`
+	RecoveredErr *jsonError      `json:"recovered_error,omitempty"`
+	StackTrace   string          `json:"stack,omitempty"`
+	Frames       []StackFrame    `json:"frames,omitempty"`
+}
+
+// jsonEncoder is implemented by errors that have their own representation in the
+// JSON schema.
+type jsonEncoder interface {
+	encodeJSON() *jsonError
+}
+
+// encodeJSONErr converts err into it's JSON representation. Errors from this package
+// keep their structure, and any other error is encoded as an OpaqueError.
+func encodeJSONErr(err error) *jsonError {
+	if err == nil {
+		return nil
+	}
+
+	if encoder, ok := err.(jsonEncoder); ok {
+		return encoder.encodeJSON()
+	}
+
+	return OpaqueError{
+		TypeName: fmt.Sprintf("%T", err),
+		Message:  err.Error(),
+	}.encodeJSON()
+}
+
+// encodeJSONErrs converts every error in errs into it's JSON representation.
+func encodeJSONErrs(errs []error) []*jsonError {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	encoded := make([]*jsonError, len(errs))
+	for i, err := range errs {
+		encoded[i] = encodeJSONErr(err)
+	}
+	return encoded
+}
+
+// jsonTime returns nil if t is zero, so it is left out of the encoding.
+func jsonTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// encodeJSON implements jsonEncoder.
+func (err OpError) encodeJSON() *jsonError {
+	return &jsonError{
+		Kind:            jsonKindOpError,
+		Message:         err.Error(),
+		OpName:          err.OpName,
+		Err:             encodeJSONErr(err.Err),
+		Attempts:        err.Attempts,
+		AttemptErrs:     encodeJSONErrs(err.AttemptErrs()),
+		TimedOut:        err.TimedOut,
+		Fallout:         err.Fallout,
+		Started:         jsonTime(err.Started),
+		Ended:           jsonTime(err.Ended),
+		LaunchIndex:     err.LaunchIndex,
+		CollectionIndex: err.CollectionIndex,
+	}
+}
+
+// encodeJSON implements jsonEncoder.
+func (err GroupErrors) encodeJSON() *jsonError {
+	// Error panics on an empty GroupErrors, so build the message ourselves.
+	message := "0 errors returned"
+	if len(err.Errs) > 0 {
+		message = err.Error()
+	}
+
+	return &jsonError{
+		Kind:         jsonKindGroupErrors,
+		Message:      message,
+		MatchMode:    err.MatchMode.String(),
+		DisplayLimit: err.DisplayLimit,
+		Errs:         encodeJSONErrs(err.Errs),
+		Total:        err.Total,
+		TypeCounts:   err.TypeCounts,
+	}
+}
+
+// encodeJSON implements jsonEncoder.
+func (err PanicError) encodeJSON() *jsonError {
+	encoded := &jsonError{
+		Kind:         jsonKindPanicError,
+		Message:      err.Error(),
+		RecoveredErr: encodeJSONErr(err.RecoveredErr),
+		StackTrace:   err.StackTrace,
+		Frames:       err.Frames,
+	}
+
+	// Recovered errors are restored from RecoveredErr, so only encode other values.
+	if _, isErr := err.Recovered.(error); !isErr && err.Recovered != nil {
+		recovered, marshalErr := json.Marshal(err.Recovered)
+		if marshalErr != nil {
+			recovered, _ = json.Marshal(fmt.Sprint(err.Recovered))
+		}
+		encoded.Recovered = recovered
+	}
+
+	return encoded
+}
+
+// encodeJSON implements jsonEncoder.
+func (err GoexitError) encodeJSON() *jsonError {
+	return &jsonError{
+		Kind:       jsonKindGoexitError,
+		Message:    err.Error(),
+		StackTrace: err.StackTrace,
+		Frames:     err.Frames,
+	}
+}
+
+// encodeJSON implements jsonEncoder. An AggregatedErrors is encoded as it's Group, as
+// it's AggregateKey cannot be encoded.
+func (err AggregatedErrors) encodeJSON() *jsonError {
+	return err.Group.encodeJSON()
+}
+
+// encodeJSON implements jsonEncoder.
+func (err OpaqueError) encodeJSON() *jsonError {
+	return &jsonError{
+		Kind:    jsonKindOpaque,
+		Message: err.Message,
+		Type:    err.TypeName,
+	}
+}
+
+// MarshalJSON implements json.Marshaler. Err and AttemptErrs are encoded as nested
+// errors.
+func (err OpError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(err.encodeJSON())
+}
+
+// MarshalJSON implements json.Marshaler. Errs are encoded as nested errors.
+func (err GroupErrors) MarshalJSON() ([]byte, error) {
+	return json.Marshal(err.encodeJSON())
+}
+
+// MarshalJSON implements json.Marshaler. Recovered is encoded with json.Marshal if
+// possible, or as it's fmt.Sprint text if not. If Recovered is an error, it is not
+// encoded separately from RecoveredErr.
+func (err PanicError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(err.encodeJSON())
+}
+
+// MarshalJSON implements json.Marshaler.
+func (err GoexitError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(err.encodeJSON())
+}
+
+// MarshalJSON implements json.Marshaler. The error is encoded as it's Group, so
+// UnmarshalError restores a GroupErrors. Buckets are not encoded, as the AggregateKey
+// that built them cannot be; call GroupErrors.Summarize on the restored value to
+// rebuild them.
+func (err AggregatedErrors) MarshalJSON() ([]byte, error) {
+	return json.Marshal(err.encodeJSON())
+}
+
+// MarshalJSON implements json.Marshaler.
+func (err OpaqueError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(err.encodeJSON())
+}
+
+// UnmarshalError rebuilds an error tree from JSON written by the MarshalJSON method
+// of OpError, GroupErrors, PanicError or GoexitError. Errors from this package are
+// restored with their original types and fields. Any other error is restored as an
+// OpaqueError.
+//
+// The recovered value of a PanicError is restored as decoded by json.Unmarshal into
+// an interface{}, so it may not have it's original type.
+//
+// Like json.Unmarshal, the rebuilt error is stored in the value pointed to by dest,
+// and the returned error is non-nil if data could not be decoded. dest is left
+// unchanged on failure.
+func UnmarshalError(data []byte, dest *error) error {
+	encoded := new(jsonError)
+	if err := json.Unmarshal(data, encoded); err != nil {
+		return err
+	}
+
+	decoded, err := encoded.decode()
+	if err != nil {
+		return err
+	}
+	*dest = decoded
+	return nil
+}
+
+// decode rebuilds the error encoded by encoded.
+func (encoded *jsonError) decode() (error, error) {
+	if encoded == nil {
+		return nil, nil
+	}
+
+	switch encoded.Kind {
+	case jsonKindOpError:
+		return encoded.decodeOpError()
+	case jsonKindGroupErrors:
+		return encoded.decodeGroupErrors()
+	case jsonKindPanicError:
+		return encoded.decodePanicError()
+	case jsonKindGoexitError:
+		return GoexitError{StackTrace: encoded.StackTrace, Frames: encoded.Frames}, nil
+	case jsonKindOpaque:
+		return OpaqueError{TypeName: encoded.Type, Message: encoded.Message}, nil
+	default:
+		return nil, fmt.Errorf("unknown error kind %q", encoded.Kind)
+	}
+}
+
+// decodeAll rebuilds every error in encoded.
+func decodeAll(encoded []*jsonError) ([]error, error) {
+	if len(encoded) == 0 {
+		return nil, nil
+	}
+
+	errs := make([]error, len(encoded))
+	for i, thisEncoded := range encoded {
+		var decodeErr error
+		if errs[i], decodeErr = thisEncoded.decode(); decodeErr != nil {
+			return nil, decodeErr
+		}
+	}
+	return errs, nil
+}
+
+// decodeOpError rebuilds an OpError.
+func (encoded *jsonError) decodeOpError() (error, error) {
+	opErr := OpError{
+		OpName:          encoded.OpName,
+		Attempts:        encoded.Attempts,
+		TimedOut:        encoded.TimedOut,
+		Fallout:         encoded.Fallout,
+		LaunchIndex:     encoded.LaunchIndex,
+		CollectionIndex: encoded.CollectionIndex,
+	}
+
+	if encoded.Started != nil {
+		opErr.Started = *encoded.Started
+	}
+	if encoded.Ended != nil {
+		opErr.Ended = *encoded.Ended
+	}
+
+	var err error
+	if opErr.Err, err = encoded.Err.decode(); err != nil {
+		return nil, err
+	}
+	attemptErrs, err := decodeAll(encoded.AttemptErrs)
+	if err != nil {
+		return nil, err
+	}
+	if attemptErrs != nil {
+		opErr.attemptErrs = &attemptErrs
+	}
+
+	return opErr, nil
+}
+
+// decodeGroupErrors rebuilds a GroupErrors.
+func (encoded *jsonError) decodeGroupErrors() (error, error) {
+	groupErr := GroupErrors{
+		MatchMode:    GroupMatchFirst,
+		DisplayLimit: encoded.DisplayLimit,
+		Total:        encoded.Total,
+		TypeCounts:   encoded.TypeCounts,
+	}
+
+	var err error
+	if encoded.MatchMode != "" {
+		if groupErr.MatchMode, err = decodeMatchMode(encoded.MatchMode); err != nil {
+			return nil, err
+		}
+	}
+	if groupErr.Errs, err = decodeAll(encoded.Errs); err != nil {
+		return nil, err
+	}
+
+	return groupErr, nil
+}
+
+// decodeMatchMode parses a GroupMatchMode from the name returned by it's String
+// method.
+func decodeMatchMode(name string) (GroupMatchMode, error) {
+	for _, mode := range []GroupMatchMode{
+		GroupMatchNone, GroupMatchAny, GroupMatchFirst, GroupMatchRootCauses,
+	} {
+		if mode.String() == name {
+			return mode, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown GroupMatchMode %q", name)
+}
+
+// decodePanicError rebuilds a PanicError.
+func (encoded *jsonError) decodePanicError() (error, error) {
+	panicErr := PanicError{
+		StackTrace: encoded.StackTrace,
+		Frames:     encoded.Frames,
+	}
+
+	var err error
+	if panicErr.RecoveredErr, err = encoded.RecoveredErr.decode(); err != nil {
+		return nil, err
+	}
+
+	if len(encoded.Recovered) != 0 {
+		if err = json.Unmarshal(encoded.Recovered, &panicErr.Recovered); err != nil {
+			return nil, err
+		}
+	} else {
+		panicErr.Recovered = panicErr.RecoveredErr
+	}
+
+	return panicErr, nil
+}
@@ -0,0 +1,248 @@
+package pears_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/peake100/pears-go/pkg/pears"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// retriedOpError runs an op named name that returns each of attemptErrs in turn, and
+// returns the OpError it's Group collects.
+func retriedOpError(t *testing.T, name string, attemptErrs ...error) pears.OpError {
+	group := pears.NewGroup(context.Background())
+
+	attempt := 0
+	group.GoNamed(
+		name,
+		func(ctx context.Context) error {
+			err := attemptErrs[attempt]
+			attempt++
+			return err
+		},
+		pears.OpWithRetry(pears.RetryPolicy{MaxAttempts: len(attemptErrs)}),
+	)
+
+	opErr := pears.OpError{}
+	if !assert.ErrorAs(t, group.Wait(), &opErr, "retried op error") {
+		t.FailNow()
+	}
+	return opErr
+}
+
+func TestJSON_RoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	started := time.Date(2021, 3, 4, 5, 6, 7, 8, time.UTC)
+	panicErr := pears.CatchPanic(func() (innerErr error) {
+		panic("boom")
+	}).(pears.PanicError)
+
+	// Attempt errors are only recorded by running an op, so we get our retried error
+	// from a Group and fill in the rest of the metadata by hand.
+	fetchOriginal := retriedOpError(t, "fetch", io.ErrUnexpectedEOF, io.EOF)
+	fetchOriginal.TimedOut = true
+	fetchOriginal.Started = started
+	fetchOriginal.Ended = started.Add(time.Second)
+	fetchOriginal.LaunchIndex = 3
+	fetchOriginal.CollectionIndex = 1
+
+	original := pears.GroupErrors{
+		MatchMode:    pears.GroupMatchRootCauses,
+		DisplayLimit: 5,
+		Errs: []error{
+			fetchOriginal,
+			pears.OpError{OpName: "panics", Err: panicErr, Fallout: true},
+			pears.OpError{
+				OpName: "shard",
+				Err: pears.GroupErrors{
+					MatchMode: pears.GroupMatchAny,
+					Errs:      []error{pears.OpError{OpName: "file", Err: io.EOF}},
+				},
+			},
+		},
+	}
+
+	data, err := json.Marshal(original)
+	if !assert.NoError(err, "marshal") {
+		t.FailNow()
+	}
+
+	var restored error
+	err = pears.UnmarshalError(data, &restored)
+	if !assert.NoError(err, "unmarshal") {
+		t.FailNow()
+	}
+
+	groupErr, ok := restored.(pears.GroupErrors)
+	if !assert.True(ok, "restored as GroupErrors") {
+		t.FailNow()
+	}
+
+	assert.Equal(pears.GroupMatchRootCauses, groupErr.MatchMode, "match mode")
+	assert.Equal(5, groupErr.DisplayLimit, "display limit")
+	assert.Equal(original.Error(), groupErr.Error(), "message")
+	if !assert.Len(groupErr.Errs, 3, "errs") {
+		t.FailNow()
+	}
+
+	// Check the OpError metadata.
+	fetchErr := groupErr.Errs[0].(pears.OpError)
+	assert.Equal("fetch", fetchErr.OpName, "op name")
+	assert.Equal(2, fetchErr.Attempts, "attempts")
+	assert.True(fetchErr.TimedOut, "timed out")
+	assert.True(started.Equal(fetchErr.Started), "started")
+	assert.Equal(time.Second, fetchErr.Duration(), "duration")
+	assert.Equal(3, fetchErr.LaunchIndex, "launch index")
+	assert.Equal(1, fetchErr.CollectionIndex, "collection index")
+	assert.Equal(
+		[]error{
+			pears.OpaqueError{TypeName: "*errors.errorString", Message: "unexpected EOF"},
+			pears.OpaqueError{TypeName: "*errors.errorString", Message: "EOF"},
+		},
+		fetchErr.AttemptErrs(),
+		"attempt errors",
+	)
+
+	// Check the PanicError.
+	restoredPanic := pears.PanicError{}
+	assert.ErrorAs(groupErr.Errs[1], &restoredPanic, "panic error")
+	assert.Equal("boom", restoredPanic.Recovered, "recovered")
+	assert.Equal(panicErr.StackTrace, restoredPanic.StackTrace, "stack")
+	assert.Equal(panicErr.Frames, restoredPanic.Frames, "frames")
+	assert.Equal(panicErr.Error(), restoredPanic.Error(), "panic message")
+	assert.True(groupErr.Errs[1].(pears.OpError).Fallout, "fallout")
+
+	// Check the nested group.
+	assert.Equal(original.Errs[2].Error(), groupErr.Errs[2].Error(), "nested message")
+	nested := groupErr.Errs[2].(pears.OpError).Err.(pears.GroupErrors)
+	assert.Equal(pears.GroupMatchAny, nested.MatchMode, "nested match mode")
+}
+
+func TestJSON_Schema(t *testing.T) {
+	data, err := json.Marshal(pears.OpError{OpName: "read", Err: os.ErrNotExist})
+	if !assert.NoError(t, err, "marshal") {
+		t.FailNow()
+	}
+
+	assert.JSONEq(t, `{
+		"kind": "op_error",
+		"message": "error during 'read': file does not exist",
+		"op_name": "read",
+		"error": {
+			"kind": "opaque",
+			"message": "file does not exist",
+			"type": "*errors.errorString"
+		}
+	}`, string(data))
+}
+
+func TestJSON_PanicErrorRecoveredError(t *testing.T) {
+	assert := assert.New(t)
+
+	panicErr := pears.CatchPanic(func() (innerErr error) {
+		panic(io.EOF)
+	})
+
+	data, err := json.Marshal(panicErr)
+	if !assert.NoError(err, "marshal") {
+		t.FailNow()
+	}
+
+	var restored error
+	err = pears.UnmarshalError(data, &restored)
+	if !assert.NoError(err, "unmarshal") {
+		t.FailNow()
+	}
+
+	restoredPanic := restored.(pears.PanicError)
+	assert.Equal(restoredPanic.RecoveredErr, restoredPanic.Recovered, "recovered")
+	assert.Equal("EOF", restoredPanic.RecoveredErr.Error(), "recovered err")
+}
+
+func TestUnmarshalError_Invalid(t *testing.T) {
+	// dest should be left alone when decoding fails.
+	restored := error(io.EOF)
+
+	err := pears.UnmarshalError([]byte(`{"kind": "unknown"}`), &restored)
+	assert.EqualError(t, err, `unknown error kind "unknown"`)
+
+	err = pears.UnmarshalError(
+		[]byte(`{"kind": "group_errors", "match_mode": "bad"}`), &restored,
+	)
+	assert.Error(t, err, "bad match mode")
+
+	err = pears.UnmarshalError([]byte(`not json`), &restored)
+	var syntaxErr *json.SyntaxError
+	assert.True(t, errors.As(err, &syntaxErr), "syntax error")
+
+	assert.Equal(t, io.EOF, restored, "dest unchanged")
+}
+
+func TestJSON_GroupMatchMode_EncodedAsInt(t *testing.T) {
+	// Only the JSON encoding of a GroupErrors names it's match mode. A GroupMatchMode
+	// held by any other value is encoded as it's integer.
+	data, err := json.Marshal(struct{ Mode pears.GroupMatchMode }{pears.GroupMatchAny})
+	if !assert.NoError(t, err, "marshal") {
+		t.FailNow()
+	}
+	assert.JSONEq(t, fmt.Sprintf(`{"Mode": %d}`, int(pears.GroupMatchAny)), string(data))
+}
+
+func TestJSON_GoexitError(t *testing.T) {
+	assert := assert.New(t)
+
+	goexitErr := pears.CatchPanic(func() (innerErr error) {
+		runtime.Goexit()
+		return nil
+	}).(pears.GoexitError)
+
+	data, err := json.Marshal(goexitErr)
+	if !assert.NoError(err, "marshal") {
+		t.FailNow()
+	}
+
+	var restored error
+	err = pears.UnmarshalError(data, &restored)
+	if !assert.NoError(err, "unmarshal") {
+		t.FailNow()
+	}
+	assert.Equal(goexitErr, restored, "restored")
+}
+
+func TestJSON_AggregatedErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	original := pears.GroupErrors{
+		MatchMode: pears.GroupMatchAny,
+		Errs: []error{
+			pears.OpError{OpName: "first", Err: io.EOF},
+			pears.OpError{OpName: "second", Err: io.EOF},
+		},
+	}
+
+	data, err := json.Marshal(original.Summarize(nil))
+	if !assert.NoError(err, "marshal") {
+		t.FailNow()
+	}
+
+	var restored error
+	err = pears.UnmarshalError(data, &restored)
+	if !assert.NoError(err, "unmarshal") {
+		t.FailNow()
+	}
+
+	groupErr, ok := restored.(pears.GroupErrors)
+	if !assert.True(ok, "restored as GroupErrors") {
+		t.FailNow()
+	}
+	assert.Equal(original.Error(), groupErr.Error(), "message")
+	assert.Equal(2, groupErr.Summarize(nil).Buckets[0].Count(), "re-summarized")
+}
@@ -0,0 +1,94 @@
+package pears
+
+import "time"
+
+// OpInfo describes an op launched by a Group.
+type OpInfo struct {
+	// Name is the name of the op.
+	Name string
+	// LaunchIndex is the order the op was launched in by it's Group, starting at 0.
+	LaunchIndex int
+	// Started is when the op started. Zero if the op has not started.
+	Started time.Time
+}
+
+// GroupObserver receives lifecycle events from a Group, and can be added with
+// WithObserver. It is a single extension point for metrics, logging and tracing.
+//
+// Methods are called synchronously from the routine where the event happens, so
+// implementations must be safe for concurrent use and should return quickly.
+type GroupObserver interface {
+	// OpQueued is called when an op is launched with Go or GoNamed, or a sub-group is
+	// created with SubGroup.
+	OpQueued(info OpInfo)
+	// OpStarted is called when an op starts running. Ops that are never started
+	// because of WithMaxConcurrency never call OpStarted.
+	OpStarted(info OpInfo)
+	// OpFinished is called when an op returns, including all retries. err is the
+	// OpError that will be collected, or nil if the op succeeded. duration is 0 if the
+	// op was never started.
+	OpFinished(info OpInfo, duration time.Duration, err error)
+	// AbortTriggered is called when the Group aborts, with the OpError that caused
+	// the abort.
+	AbortTriggered(cause OpError)
+	// WaitReturned is called with the result of Wait or WaitContext just before it
+	// returns.
+	WaitReturned(err error)
+}
+
+// NoopObserver implements GroupObserver with methods that do nothing. It can be
+// embedded in another type to implement only some of GroupObserver's methods.
+type NoopObserver struct{}
+
+// OpQueued implements GroupObserver.
+func (NoopObserver) OpQueued(info OpInfo) {}
+
+// OpStarted implements GroupObserver.
+func (NoopObserver) OpStarted(info OpInfo) {}
+
+// OpFinished implements GroupObserver.
+func (NoopObserver) OpFinished(info OpInfo, duration time.Duration, err error) {}
+
+// AbortTriggered implements GroupObserver.
+func (NoopObserver) AbortTriggered(cause OpError) {}
+
+// WaitReturned implements GroupObserver.
+func (NoopObserver) WaitReturned(err error) {}
+
+// multiObserver notifies every GroupObserver it holds of each event, in order.
+type multiObserver []GroupObserver
+
+// OpQueued implements GroupObserver.
+func (observers multiObserver) OpQueued(info OpInfo) {
+	for _, observer := range observers {
+		observer.OpQueued(info)
+	}
+}
+
+// OpStarted implements GroupObserver.
+func (observers multiObserver) OpStarted(info OpInfo) {
+	for _, observer := range observers {
+		observer.OpStarted(info)
+	}
+}
+
+// OpFinished implements GroupObserver.
+func (observers multiObserver) OpFinished(info OpInfo, duration time.Duration, err error) {
+	for _, observer := range observers {
+		observer.OpFinished(info, duration, err)
+	}
+}
+
+// AbortTriggered implements GroupObserver.
+func (observers multiObserver) AbortTriggered(cause OpError) {
+	for _, observer := range observers {
+		observer.AbortTriggered(cause)
+	}
+}
+
+// WaitReturned implements GroupObserver.
+func (observers multiObserver) WaitReturned(err error) {
+	for _, observer := range observers {
+		observer.WaitReturned(err)
+	}
+}
@@ -0,0 +1,125 @@
+package pears_test
+
+import (
+	"context"
+	"github.com/peake100/pears-go/pkg/pears"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingObserver records every event it receives.
+type recordingObserver struct {
+	lock sync.Mutex
+
+	queued   []string
+	started  []string
+	finished map[string]error
+	aborts   []pears.OpError
+	waits    []error
+}
+
+func (observer *recordingObserver) OpQueued(info pears.OpInfo) {
+	observer.lock.Lock()
+	defer observer.lock.Unlock()
+	observer.queued = append(observer.queued, info.Name)
+}
+
+func (observer *recordingObserver) OpStarted(info pears.OpInfo) {
+	observer.lock.Lock()
+	defer observer.lock.Unlock()
+	observer.started = append(observer.started, info.Name)
+}
+
+func (observer *recordingObserver) OpFinished(
+	info pears.OpInfo, duration time.Duration, err error,
+) {
+	observer.lock.Lock()
+	defer observer.lock.Unlock()
+	if observer.finished == nil {
+		observer.finished = make(map[string]error)
+	}
+	observer.finished[info.Name] = err
+}
+
+func (observer *recordingObserver) AbortTriggered(cause pears.OpError) {
+	observer.lock.Lock()
+	defer observer.lock.Unlock()
+	observer.aborts = append(observer.aborts, cause)
+}
+
+func (observer *recordingObserver) WaitReturned(err error) {
+	observer.lock.Lock()
+	defer observer.lock.Unlock()
+	observer.waits = append(observer.waits, err)
+}
+
+func TestGroupObserver(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	observer := new(recordingObserver)
+	manager := pears.NewGroup(ctx, pears.WithObserver(observer))
+
+	manager.GoNamed("succeeds", func(ctx context.Context) error {
+		return nil
+	})
+	manager.GoNamed("fails", func(ctx context.Context) error {
+		return io.EOF
+	})
+
+	err := manager.Wait()
+	assert.Error(err, "group failed")
+
+	assert.ElementsMatch([]string{"succeeds", "fails"}, observer.queued, "queued")
+	assert.ElementsMatch([]string{"succeeds", "fails"}, observer.started, "started")
+
+	if assert.Len(observer.finished, 2, "2 ops finished") {
+		assert.NoError(observer.finished["succeeds"], "succeeds finished without error")
+
+		opErr := pears.OpError{}
+		if assert.ErrorAs(observer.finished["fails"], &opErr, "fails finished with OpError") {
+			assert.Equal("fails", opErr.OpName)
+		}
+	}
+
+	if assert.Len(observer.aborts, 1, "1 abort") {
+		assert.Equal("fails", observer.aborts[0].OpName, "abort caused by failing op")
+	}
+
+	if assert.Len(observer.waits, 1, "wait returned once") {
+		assert.Equal(err, observer.waits[0], "wait result passed to observer")
+	}
+}
+
+// abortObserver only cares about aborts, and embeds NoopObserver for the rest of
+// GroupObserver.
+type abortObserver struct {
+	pears.NoopObserver
+	aborted chan pears.OpError
+}
+
+func (observer abortObserver) AbortTriggered(cause pears.OpError) {
+	observer.aborted <- cause
+}
+
+func TestGroupObserver_NoopEmbedded(t *testing.T) {
+	observer := abortObserver{aborted: make(chan pears.OpError, 1)}
+
+	manager := pears.NewGroup(context.Background(), pears.WithObserver(observer))
+	manager.GoNamed("fails", func(ctx context.Context) error {
+		return io.EOF
+	})
+	_ = manager.Wait()
+
+	select {
+	case cause := <-observer.aborted:
+		assert.Equal(t, "fails", cause.OpName, "abort cause received")
+	default:
+		t.Error("abort not observed")
+	}
+}
@@ -1,7 +1,10 @@
 package pears
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"runtime"
 	"runtime/debug"
 )
 
@@ -14,6 +17,10 @@ type PanicError struct {
 	RecoveredErr error
 	// StackTrace contains the formatted stacktrace of the panic.
 	StackTrace string
+	// Frames contains the parsed frames of the panicking goroutine's stack, starting
+	// with runtime.gopanic. Use TrimmedFrames or PanicFrame to skip frames from the
+	// runtime and from this package.
+	Frames []StackFrame
 }
 
 // Error implements builtins.error.
@@ -26,10 +33,59 @@ func (err PanicError) Unwrap() error {
 	return err.RecoveredErr
 }
 
+// IsNilPanic returns true if the panic was caused by panic(nil). RecoveredErr will be a
+// *runtime.PanicNilError. Recovered will be the same *runtime.PanicNilError, or nil if
+// GODEBUG=panicnil=1 is set.
+func (err PanicError) IsNilPanic() bool {
+	var panicNilErr *runtime.PanicNilError
+	return errors.As(err.RecoveredErr, &panicNilErr)
+}
+
+// GoexitError is returned by CatchPanic when the function it ran called
+// runtime.Goexit instead of returning.
+type GoexitError struct {
+	// StackTrace contains the formatted stacktrace of the call to runtime.Goexit.
+	StackTrace string
+	// Frames contains the parsed frames of the stack, starting with runtime.Goexit.
+	Frames []StackFrame
+}
+
+// Error implements builtins.error.
+func (err GoexitError) Error() string {
+	return "runtime.Goexit called"
+}
+
+// TrimmedFrames returns Frames without any frames from the runtime or from this
+// package, leaving only the caller's code.
+func (err GoexitError) TrimmedFrames() []StackFrame {
+	return trimFrames(err.Frames)
+}
+
+// newGoexitError builds a GoexitError. It must be called directly by a function
+// deferred while runtime.Goexit unwinds the goroutine.
+func newGoexitError() GoexitError {
+	return GoexitError{
+		StackTrace: string(debug.Stack()),
+		// Skip this function and the deferred function so the frames start at
+		// runtime.Goexit.
+		Frames: captureFrames(2),
+	}
+}
+
 // CatchPanic runs mayPanic and returns it's result.
 //
 // If mayPanic panics, the panic is recovered and a PanicError is returned with the
-// recovered value.
+// recovered value. This includes panic(nil), which is reported as a PanicError that
+// wraps a *runtime.PanicNilError even when GODEBUG=panicnil=1 is set. If mayPanic calls
+// runtime.Goexit, as testing.T.FailNow does, a GoexitError is returned. A nil error
+// always means mayPanic returned normally.
+//
+// mayPanic is run on a new goroutine so a call to runtime.Goexit can be reported
+// rather than ending the caller's goroutine. CatchPanic blocks until that goroutine
+// exits. This costs a goroutine per call, and means the stack trace and frames of a
+// PanicError or GoexitError end at that goroutine: they do not include the frames of
+// the function that called CatchPanic. Group does not pay this cost, as it detects
+// runtime.Goexit on the routine each op already runs on.
 //
 // It is not generally a good pattern to use panic recovery as a throw/catch mechanism
 // like other languages support. This function should be used sparingly in contexts
@@ -37,34 +93,113 @@ func (err PanicError) Unwrap() error {
 // framework catching panics from an endpoint handler so an error status can be returned
 // to the original requester.
 func CatchPanic(mayPanic func() (innerErr error)) (err error) {
+	_, err = CatchPanicValue(func() (struct{}, error) {
+		return struct{}{}, mayPanic()
+	})
+	return err
+}
+
+// CatchPanicValue runs mayPanic and returns it's result. If mayPanic panics or calls
+// runtime.Goexit, the zero value of T is returned with a PanicError or GoexitError, as
+// in CatchPanic.
+func CatchPanicValue[T any](mayPanic func() (T, error)) (value T, err error) {
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		// returned is set if recoverPanic returns. If it is not set when we exit,
+		// mayPanic called runtime.Goexit.
+		returned := false
+		defer func() {
+			if returned {
+				return
+			}
+			var zero T
+			value = zero
+			err = newGoexitError()
+		}()
+
+		value, err = recoverPanic(mayPanic)
+		returned = true
+	}()
+
+	<-done
+	return value, err
+}
+
+// recoverPanic runs mayPanic on the current goroutine and returns it's result, or a
+// PanicError if it panics.
+//
+// If mayPanic calls runtime.Goexit, recoverPanic never returns and the goroutine keeps
+// exiting. Callers detect this with a deferred function of their own that checks
+// whether recoverPanic returned.
+func recoverPanic[T any](mayPanic func() (T, error)) (value T, err error) {
+	normalReturn := false
+
 	// Defer catching a panic.
 	defer func() {
-		var stacktrace []byte
-		var recovered interface{}
-		// If there is nothing to recover, return.
-		if recovered = recover(); recovered == nil {
+		if normalReturn {
 			return
 		}
+		// recover returns nil for a Goexit, as well as for panic(nil) under
+		// GODEBUG=panicnil=1. A Goexit keeps unwinding past us, so the PanicError built
+		// here is only returned for a real panic.
+		var zero T
+		value = zero
+		err = newPanicError(recover())
+	}()
+
+	// Run the caller's function.
+	value, err = mayPanic()
+	normalReturn = true
+	return value, err
+}
 
-		stacktrace = debug.Stack()
+// CatchPanicCtx runs mayPanic with ctx and returns it's result. If mayPanic panics, a
+// PanicError is returned, as in CatchPanic.
+func CatchPanicCtx(
+	ctx context.Context, mayPanic func(ctx context.Context) (innerErr error),
+) (err error) {
+	return CatchPanic(func() error {
+		return mayPanic(ctx)
+	})
+}
 
-		// Check if the recovered value is an error.
-		var recoveredErr error
-		var ok bool
-		if recoveredErr, ok = recovered.(error); !ok {
-			// If it is not, convert it to one.
-			recoveredErr = fmt.Errorf("%v", recovered)
-		}
+// CatchPanicFunc runs mayPanic, and returns a PanicError if it panics, as in
+// CatchPanic. Returns nil if mayPanic returns.
+func CatchPanicFunc(mayPanic func()) (err error) {
+	return CatchPanic(func() error {
+		mayPanic()
+		return nil
+	})
+}
 
-		// Set the return error to a PanicError.
-		err = PanicError{
-			Recovered:    recovered,
-			RecoveredErr: recoveredErr,
-			StackTrace:   string(stacktrace),
-		}
-	}()
+// newPanicError builds the PanicError for recovered. It must be called directly by the
+// deferred function that recovered the panic, so the stack trace is captured before
+// the panicking goroutine unwinds.
+func newPanicError(recovered interface{}) PanicError {
+	stacktrace := debug.Stack()
+	// Skip this function and the deferred function so the frames start at
+	// runtime.gopanic.
+	frames := captureFrames(2)
 
-	// Run the caller's function.
-	err = mayPanic()
-	return err
+	// Check if the recovered value is an error.
+	var recoveredErr error
+	var ok bool
+	if recovered == nil {
+		// panic(nil) only recovers nil under GODEBUG=panicnil=1. Report it the same way
+		// it is reported without the setting.
+		recoveredErr = new(runtime.PanicNilError)
+	} else if recoveredErr, ok = recovered.(error); !ok {
+		// If it is not, convert it to one.
+		recoveredErr = fmt.Errorf("%v", recovered)
+	}
+
+	return PanicError{
+		Recovered:    recovered,
+		RecoveredErr: recoveredErr,
+		StackTrace:   string(stacktrace),
+		Frames:       frames,
+	}
 }
@@ -1,10 +1,12 @@
 package pears_test
 
 import (
+	"context"
 	"errors"
 	"github.com/peake100/pears-go/pkg/pears"
 	"github.com/stretchr/testify/assert"
 	"io"
+	"runtime"
 	"testing"
 )
 
@@ -97,3 +99,199 @@ func TestCatchPanic_Panic_IntValue(t *testing.T) {
 	)
 	t.Log("STACKTRACE:\n", panicErr.StackTrace)
 }
+
+func TestCatchPanicValue(t *testing.T) {
+	assert := assert.New(t)
+
+	value, err := pears.CatchPanicValue(func() (int, error) {
+		return 10, io.EOF
+	})
+	assert.Equal(10, value, "value returned")
+	assert.ErrorIs(err, io.EOF, "error returned")
+
+	value, err = pears.CatchPanicValue(func() (int, error) {
+		panic("boom")
+	})
+	assert.Equal(0, value, "zero value returned")
+
+	panicErr := pears.PanicError{}
+	if !assert.ErrorAs(err, &panicErr, "error is panic error") {
+		t.FailNow()
+	}
+	assert.Equal("boom", panicErr.Recovered, "recovered value")
+	assert.NotEmpty(panicErr.StackTrace, "stack trace")
+	assert.NotEmpty(panicErr.TrimmedFrames(), "frames")
+}
+
+func TestCatchPanicCtx(t *testing.T) {
+	assert := assert.New(t)
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+
+	err := pears.CatchPanicCtx(ctx, func(ctx context.Context) error {
+		panic(ctx.Value(ctxKey{}))
+	})
+
+	panicErr := pears.PanicError{}
+	if !assert.ErrorAs(err, &panicErr, "error is panic error") {
+		t.FailNow()
+	}
+	assert.Equal("value", panicErr.Recovered, "ctx passed to function")
+	assert.NotEmpty(panicErr.StackTrace, "stack trace")
+
+	err = pears.CatchPanicCtx(ctx, func(ctx context.Context) error {
+		return io.EOF
+	})
+	assert.ErrorIs(err, io.EOF, "error returned")
+}
+
+func TestCatchPanicFunc(t *testing.T) {
+	assert := assert.New(t)
+
+	called := false
+	err := pears.CatchPanicFunc(func() {
+		called = true
+	})
+	assert.NoError(err, "no error returned")
+	assert.True(called, "function called")
+
+	err = pears.CatchPanicFunc(func() {
+		panic(io.EOF)
+	})
+
+	panicErr := pears.PanicError{}
+	if !assert.ErrorAs(err, &panicErr, "error is panic error") {
+		t.FailNow()
+	}
+	assert.ErrorIs(err, io.EOF, "recovered error")
+
+	frame, ok := panicErr.PanicFrame()
+	assert.True(ok, "panic frame found")
+	assert.Equal(
+		"github.com/peake100/pears-go/pkg/pears_test.TestCatchPanicFunc.func2",
+		frame.Function,
+		"panic frame",
+	)
+}
+
+func TestCatchPanic_Goexit(t *testing.T) {
+	assert := assert.New(t)
+
+	deferRan := false
+	err := pears.CatchPanic(func() (innerErr error) {
+		defer func() {
+			deferRan = true
+		}()
+		runtime.Goexit()
+		return nil
+	})
+
+	goexitErr := pears.GoexitError{}
+	if !assert.ErrorAs(err, &goexitErr, "error is GoexitError") {
+		t.FailNow()
+	}
+	assert.EqualError(err, "runtime.Goexit called")
+	assert.True(deferRan, "deferred functions ran")
+	assert.Contains(goexitErr.StackTrace, "runtime.Goexit", "stack trace")
+	assert.Equal("runtime.Goexit", goexitErr.Frames[0].Function, "first frame")
+
+	trimmed := goexitErr.TrimmedFrames()
+	if assert.NotEmpty(trimmed, "trimmed frames") {
+		assert.Equal(
+			"github.com/peake100/pears-go/pkg/pears_test.TestCatchPanic_Goexit.func1",
+			trimmed[0].Function,
+			"caller frame",
+		)
+	}
+
+	var panicErr pears.PanicError
+	assert.False(errors.As(err, &panicErr), "error is not PanicError")
+}
+
+func TestCatchPanicValue_Goexit(t *testing.T) {
+	assert := assert.New(t)
+
+	value, err := pears.CatchPanicValue(func() (int, error) {
+		defer runtime.Goexit()
+		return 10, nil
+	})
+
+	assert.Equal(0, value, "zero value returned")
+	assert.ErrorAs(err, new(pears.GoexitError), "error is GoexitError")
+}
+
+func TestCatchPanic_NilPanic(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		PanicNil  bool
+		Recovered bool
+	}{
+		{
+			Name:      "Default",
+			PanicNil:  false,
+			Recovered: true,
+		},
+		{
+			Name:      "GodebugPanicNil",
+			PanicNil:  true,
+			Recovered: false,
+		},
+	}
+
+	for _, thisCase := range testCases {
+		t.Run(thisCase.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			if thisCase.PanicNil {
+				t.Setenv("GODEBUG", "panicnil=1")
+			}
+
+			err := pears.CatchPanic(func() (innerErr error) {
+				panic(nil)
+			})
+
+			panicErr := pears.PanicError{}
+			if !assert.ErrorAs(err, &panicErr, "error is PanicError") {
+				t.FailNow()
+			}
+
+			assert.True(panicErr.IsNilPanic(), "nil panic")
+			assert.Equal(thisCase.Recovered, panicErr.Recovered != nil, "recovered")
+
+			var panicNilErr *runtime.PanicNilError
+			assert.ErrorAs(err, &panicNilErr, "error is *runtime.PanicNilError")
+			assert.NotEmpty(panicErr.StackTrace, "stack trace")
+		})
+	}
+}
+
+func TestPanicError_IsNilPanic_False(t *testing.T) {
+	err := pears.CatchPanic(func() (innerErr error) {
+		panic("boom")
+	})
+
+	panicErr := pears.PanicError{}
+	if !assert.ErrorAs(t, err, &panicErr, "error is PanicError") {
+		t.FailNow()
+	}
+	assert.False(t, panicErr.IsNilPanic(), "not a nil panic")
+}
+
+func TestGroup_OpGoexit(t *testing.T) {
+	assert := assert.New(t)
+
+	group := pears.NewGroup(context.Background())
+	group.GoNamed("fails now", func(ctx context.Context) error {
+		runtime.Goexit()
+		return nil
+	})
+
+	err := group.Wait()
+	assert.ErrorAs(err, new(pears.GoexitError), "error is GoexitError")
+
+	opErr := pears.OpError{}
+	if assert.ErrorAs(err, &opErr, "error is OpError") {
+		assert.Equal("fails now", opErr.OpName, "op name")
+	}
+}
@@ -0,0 +1,91 @@
+package pears
+
+import (
+	"context"
+	"strconv"
+)
+
+// Map runs fn on every item in items concurrently using a Group, and returns the
+// results in the same order as items.
+//
+// Each op is named by the index of it's item, so the OpName of any OpError in the
+// returned GroupErrors can be converted back into an index with strconv.Atoi. The
+// result for any item that failed is the zero value of Out.
+//
+// opts configure the underlying Group the same way they do for NewGroup.
+func Map[In any, Out any](
+	ctx context.Context,
+	items []In,
+	fn func(ctx context.Context, item In) (Out, error),
+	opts ...GroupOption,
+) ([]Out, error) {
+	return MapKeyed(ctx, items, indexKey[In], fn, opts...)
+}
+
+// MapKeyed is the same as Map, but names each op with the result of key, allowing
+// OpError values to be traced back to their failing inputs by a more meaningful name
+// than their index.
+func MapKeyed[In any, Out any](
+	ctx context.Context,
+	items []In,
+	key func(index int, item In) string,
+	fn func(ctx context.Context, item In) (Out, error),
+	opts ...GroupOption,
+) ([]Out, error) {
+	results := make([]Out, len(items))
+	group := NewGroup(ctx, opts...)
+
+	for i, item := range items {
+		// Each op writes to it's own slot in results, so no lock is needed.
+		index, thisItem := i, item
+		group.GoNamed(key(index, thisItem), func(ctx context.Context) error {
+			result, err := fn(ctx, thisItem)
+			if err != nil {
+				return err
+			}
+			results[index] = result
+			return nil
+		})
+	}
+
+	err := group.Wait()
+	return results, err
+}
+
+// ForEach runs fn on every item in items concurrently using a Group. Ops are named
+// by item index the same way they are in Map.
+//
+// opts configure the underlying Group the same way they do for NewGroup.
+func ForEach[In any](
+	ctx context.Context,
+	items []In,
+	fn func(ctx context.Context, item In) error,
+	opts ...GroupOption,
+) error {
+	return ForEachKeyed(ctx, items, indexKey[In], fn, opts...)
+}
+
+// ForEachKeyed is the same as ForEach, but names each op with the result of key.
+func ForEachKeyed[In any](
+	ctx context.Context,
+	items []In,
+	key func(index int, item In) string,
+	fn func(ctx context.Context, item In) error,
+	opts ...GroupOption,
+) error {
+	_, err := MapKeyed(
+		ctx,
+		items,
+		key,
+		func(ctx context.Context, item In) (struct{}, error) {
+			return struct{}{}, fn(ctx, item)
+		},
+		opts...,
+	)
+	return err
+}
+
+// indexKey names an op by the index of it's item.
+func indexKey[In any](index int, _ In) string {
+	return strconv.Itoa(index)
+}
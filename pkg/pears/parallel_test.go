@@ -0,0 +1,106 @@
+package pears_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/peake100/pears-go/pkg/pears"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestMap_OrderPreserved(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	items := []int{5, 4, 3, 2, 1}
+
+	results, err := pears.Map(ctx, items, func(ctx context.Context, item int) (string, error) {
+		// Make earlier items finish last.
+		time.Sleep(time.Duration(item) * time.Millisecond)
+		return fmt.Sprint("item", item), nil
+	})
+
+	assert.NoError(err, "no errors")
+	assert.Equal(
+		[]string{"item5", "item4", "item3", "item2", "item1"},
+		results,
+		"results are in input order",
+	)
+}
+
+func TestMap_ErrPointsToInput(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	errOdd := errors.New("odd item")
+	items := []int{0, 2, 3, 4}
+
+	results, err := pears.Map(
+		ctx,
+		items,
+		func(ctx context.Context, item int) (int, error) {
+			if item%2 == 1 {
+				return 0, errOdd
+			}
+			return item * 10, nil
+		},
+		pears.WithAbortOnError(false),
+	)
+
+	assert.Equal([]int{0, 20, 0, 40}, results, "successful results kept")
+
+	batchErrs := pears.GroupErrors{}
+	if !assert.ErrorAs(err, &batchErrs) {
+		t.FailNow()
+	}
+	if !assert.Len(batchErrs.Errs, 1, "one item failed") {
+		t.FailNow()
+	}
+
+	opErr := pears.OpError{}
+	if !assert.ErrorAs(batchErrs.Errs[0], &opErr) {
+		t.FailNow()
+	}
+	index, convErr := strconv.Atoi(opErr.OpName)
+	if !assert.NoError(convErr, "op name is index") {
+		t.FailNow()
+	}
+	assert.Equal(3, items[index], "op error points to failing input")
+	assert.ErrorIs(opErr, errOdd)
+}
+
+func TestForEachKeyed(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	items := []string{"a", "b", "c"}
+
+	err := pears.ForEachKeyed(
+		ctx,
+		items,
+		func(index int, item string) string {
+			return "item-" + item
+		},
+		func(ctx context.Context, item string) error {
+			if item == "b" {
+				return fmt.Errorf("bad item")
+			}
+			return nil
+		},
+	)
+
+	opErr := pears.OpError{}
+	if !assert.ErrorAs(err, &opErr) {
+		t.FailNow()
+	}
+	assert.Equal("item-b", opErr.OpName, "op named by key func")
+}
@@ -0,0 +1,76 @@
+package pears
+
+import (
+	"context"
+	"sync"
+)
+
+// ResultGroup is a Group whose ops each return a value of type T alongside their
+// error. Results of successful ops are collected and returned by Wait, keyed by the
+// name passed to GoNamed.
+//
+// ResultGroup must be created with a constructor function: NewResultGroup.
+type ResultGroup[T any] struct {
+	// group runs our ops and collects their errors.
+	group *Group
+
+	// resultsLock guards results.
+	resultsLock sync.Mutex
+	// results stores the values returned by successful ops, keyed by op name.
+	results map[string]T
+}
+
+// GoNamed launches op in it's own routine. If op succeeds, it's result will be
+// returned by Wait under name. If op returns an error, it is collected the same way
+// Group collects errors, and the result is discarded.
+//
+// Names should be unique. If two successful ops share a name, only one of their
+// results will be kept.
+//
+// opts configure this op only, the same way they do for Group.GoNamed.
+//
+// GoNamed will panic if called after Wait.
+func (runner *ResultGroup[T]) GoNamed(
+	name string, op func(ctx context.Context) (T, error), opts ...OpOption,
+) {
+	runner.group.GoNamed(name, func(ctx context.Context) error {
+		result, err := op(ctx)
+		if err != nil {
+			return err
+		}
+
+		runner.resultsLock.Lock()
+		defer runner.resultsLock.Unlock()
+		runner.results[name] = result
+
+		return nil
+	}, opts...)
+}
+
+// Wait waits until all operations launched by GoNamed complete, then returns the
+// results of all successful ops keyed by op name. If any errors were returned by
+// operations, they will be returned as OpError values in a GroupErrors container.
+//
+// Results are returned even when some ops fail, so partial results can be used.
+//
+// Wait will panic if called multiple times. ResultGroup cannot be reused.
+func (runner *ResultGroup[T]) Wait() (map[string]T, error) {
+	err := runner.group.Wait()
+
+	runner.resultsLock.Lock()
+	defer runner.resultsLock.Unlock()
+
+	return runner.results, err
+}
+
+// NewResultGroup creates a new *ResultGroup for running concurrent operations and
+// centrally collecting their results and errors.
+//
+// ctx and opts behave the same as they do for NewGroup.
+func NewResultGroup[T any](ctx context.Context, opts ...GroupOption) *ResultGroup[T] {
+	return &ResultGroup[T]{
+		group:       NewGroup(ctx, opts...),
+		resultsLock: sync.Mutex{},
+		results:     make(map[string]T),
+	}
+}
@@ -0,0 +1,70 @@
+package pears_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/peake100/pears-go/pkg/pears"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestResultGroup_NoErrs(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	group := pears.NewResultGroup[int](ctx)
+
+	for i := 0; i < 10; i++ {
+		opIndex := i
+		group.GoNamed(fmt.Sprint("op", opIndex), func(ctx context.Context) (int, error) {
+			return opIndex * 2, nil
+		})
+	}
+
+	results, err := group.Wait()
+	assert.NoError(err, "no errors running routines")
+
+	if !assert.Len(results, 10, "10 results returned") {
+		t.FailNow()
+	}
+	for i := 0; i < 10; i++ {
+		assert.Equal(i*2, results[fmt.Sprint("op", i)], "result keyed by op name")
+	}
+}
+
+func TestResultGroup_PartialResults(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	group := pears.NewResultGroup[string](ctx, pears.WithAbortOnError(false))
+
+	group.GoNamed("succeeds", func(ctx context.Context) (string, error) {
+		return "result", nil
+	})
+
+	group.GoNamed("fails", func(ctx context.Context) (string, error) {
+		return "discarded", io.EOF
+	})
+
+	results, err := group.Wait()
+
+	batchErrs := pears.GroupErrors{}
+	if !assert.ErrorAs(err, &batchErrs) {
+		t.FailNow()
+	}
+	assert.Len(batchErrs.Errs, 1, "one op failed")
+	assert.ErrorIs(err, io.EOF, "error is io.EOF")
+
+	opErr := pears.OpError{}
+	if assert.ErrorAs(err, &opErr) {
+		assert.Equal("fails", opErr.OpName, "error from failing op")
+	}
+
+	assert.Equal(map[string]string{"succeeds": "result"}, results, "partial results")
+}
@@ -0,0 +1,99 @@
+package pears
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// RetentionMode determines which errors a RetentionPolicy keeps once it's limit has
+// been reached.
+type RetentionMode int
+
+const (
+	// RetainFirst keeps the first errors collected, and drops any that follow.
+	RetainFirst RetentionMode = iota
+	// RetainLast keeps the first error collected and the most recently collected
+	// errors, dropping the oldest of the recent errors each time a new one is
+	// collected.
+	RetainLast
+	// RetainSample keeps the first error collected and a uniform random sample of the
+	// errors that follow it.
+	RetainSample
+)
+
+// RetentionPolicy caps how many errors a Collector or Group keeps in memory. Errors
+// that are not retained are still counted by GroupErrors.Total and
+// GroupErrors.TypeCounts.
+//
+// Every mode retains the first error collected, as it is often the root cause of the
+// errors that follow and is the error GroupMatchFirst matches on. The other Max - 1
+// slots are filled according to Mode.
+//
+// The zero value retains every error.
+type RetentionPolicy struct {
+	// Mode determines which errors are kept once Max has been reached.
+	Mode RetentionMode
+	// Max is the maximum number of errors to retain. 0 means there is no limit.
+	Max int
+}
+
+// retainedErrs holds the errors kept by a RetentionPolicy. It is not safe for
+// concurrent use.
+type retainedErrs struct {
+	// policy is the RetentionPolicy in use.
+	policy RetentionPolicy
+	// errs holds the retained errors. errs[0] is always the first error collected.
+	// When policy.Mode is RetainLast and Max has been reached, errs[1:] is a ring
+	// buffer starting at next.
+	errs []error
+	// next is the index in errs[1:] the next error will overwrite under RetainLast.
+	next int
+}
+
+// add offers err to be retained. index is the number of errors offered before err.
+func (retained *retainedErrs) add(err OpError, index int) {
+	policy := retained.policy
+	if policy.Max <= 0 || len(retained.errs) < policy.Max {
+		retained.errs = append(retained.errs, err)
+		return
+	}
+
+	// The first error is never replaced, so only the slots after it are rotated or
+	// sampled.
+	slots := retained.errs[1:]
+	if len(slots) == 0 {
+		return
+	}
+
+	switch policy.Mode {
+	case RetainLast:
+		slots[retained.next] = err
+		retained.next = (retained.next + 1) % len(slots)
+	case RetainSample:
+		// Reservoir sampling over the errors after the first: err is the index'th of
+		// them, counting from 1, so keep it with a probability of len(slots) / index in
+		// place of a random sampled error.
+		if replace := rand.Intn(index); replace < len(slots) {
+			slots[replace] = err
+		}
+	}
+}
+
+// list returns a copy of the retained errors in the order they were collected.
+func (retained *retainedErrs) list() []error {
+	errs := make([]error, 0, len(retained.errs))
+	if len(retained.errs) != 0 {
+		slots := retained.errs[1:]
+		errs = append(errs, retained.errs[0])
+		errs = append(errs, slots[retained.next:]...)
+		errs = append(errs, slots[:retained.next]...)
+	}
+
+	if retained.policy.Mode == RetainSample {
+		sort.Slice(errs, func(i, j int) bool {
+			return errs[i].(OpError).CollectionIndex < errs[j].(OpError).CollectionIndex
+		})
+	}
+
+	return errs
+}
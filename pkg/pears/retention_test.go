@@ -0,0 +1,181 @@
+package pears_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/peake100/pears-go/pkg/pears"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+)
+
+// customErr is an error type used for counting errors by type.
+type customErr struct{}
+
+// Error implements builtins.error.
+func (customErr) Error() string {
+	return "custom"
+}
+
+// addNumbered adds count errors to collector, named by their index.
+func addNumbered(collector *pears.Collector, count int) {
+	for i := 0; i < count; i++ {
+		collector.Add(fmt.Sprint(i), io.EOF)
+	}
+}
+
+// retainedNames returns the OpName of every error in err.
+func retainedNames(t *testing.T, err error) []string {
+	groupErr := pears.GroupErrors{}
+	if !assert.ErrorAs(t, err, &groupErr, "error is GroupErrors") {
+		t.FailNow()
+	}
+
+	names := make([]string, len(groupErr.Errs))
+	for i, thisErr := range groupErr.Errs {
+		names[i] = thisErr.(pears.OpError).OpName
+	}
+	return names
+}
+
+func TestCollector_Retention(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Mode     pears.RetentionMode
+		Expected []string
+	}{
+		{
+			Name:     "RetainFirst",
+			Mode:     pears.RetainFirst,
+			Expected: []string{"0", "1", "2"},
+		},
+		{
+			Name:     "RetainLast",
+			Mode:     pears.RetainLast,
+			Expected: []string{"0", "8", "9"},
+		},
+	}
+
+	for _, thisCase := range testCases {
+		t.Run(thisCase.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			collector := pears.NewCollector(pears.CollectorWithRetention(
+				pears.RetentionPolicy{Mode: thisCase.Mode, Max: 3},
+			))
+			addNumbered(collector, 10)
+
+			err := collector.Err()
+			assert.Equal(thisCase.Expected, retainedNames(t, err), "retained errors")
+			assert.Equal(10, collector.Len(), "all errors counted")
+
+			groupErr := err.(pears.GroupErrors)
+			assert.Equal(10, groupErr.Total, "total")
+			assert.Equal(10, groupErr.TotalCount(), "total count")
+			assert.Equal(7, groupErr.Dropped(), "dropped")
+			assert.Equal(
+				fmt.Sprintf(
+					"10 errors (3 retained). first: error during '%v': EOF",
+					thisCase.Expected[0],
+				),
+				err.Error(),
+			)
+		})
+	}
+}
+
+func TestCollector_Retention_Sample(t *testing.T) {
+	assert := assert.New(t)
+
+	collector := pears.NewCollector(pears.CollectorWithRetention(
+		pears.RetentionPolicy{Mode: pears.RetainSample, Max: 10},
+	))
+	addNumbered(collector, 1000)
+
+	groupErr := collector.Err().(pears.GroupErrors)
+	if !assert.Len(groupErr.Errs, 10, "sample size") {
+		t.FailNow()
+	}
+	assert.Equal(1000, groupErr.Total, "total")
+
+	// The first error is always retained.
+	assert.Equal(0, groupErr.Errs[0].(pears.OpError).CollectionIndex, "first kept")
+
+	// Sampled errors should be returned in collection order.
+	for i := 1; i < len(groupErr.Errs); i++ {
+		assert.Less(
+			groupErr.Errs[i-1].(pears.OpError).CollectionIndex,
+			groupErr.Errs[i].(pears.OpError).CollectionIndex,
+			"collection order",
+		)
+	}
+}
+
+func TestCollector_TypeCounts(t *testing.T) {
+	collector := pears.NewCollector(pears.CollectorWithMaxErrs(1))
+	collector.Add("first", io.EOF)
+	collector.Add("second", customErr{})
+	collector.Add("third", customErr{})
+	collector.Add("fourth", errors.New("other"))
+
+	groupErr := collector.Err().(pears.GroupErrors)
+	assert.Equal(t, map[string]int{
+		"*errors.errorString":  2,
+		"pears_test.customErr": 2,
+	}, groupErr.TypeCounts)
+}
+
+func TestGroup_WithRetention(t *testing.T) {
+	assert := assert.New(t)
+
+	collected := 0
+	group := pears.NewGroup(
+		context.Background(),
+		pears.WithAbortOnError(false),
+		pears.WithRetention(pears.RetentionPolicy{Mode: pears.RetainFirst, Max: 5}),
+		pears.WithOnError(func(err pears.OpError) {
+			collected++
+		}),
+	)
+
+	for i := 0; i < 100; i++ {
+		group.Go(func(ctx context.Context) error {
+			return io.EOF
+		})
+	}
+
+	err := group.Wait()
+	groupErr := pears.GroupErrors{}
+	if !assert.ErrorAs(err, &groupErr, "error is GroupErrors") {
+		t.FailNow()
+	}
+
+	assert.Len(groupErr.Errs, 5, "retained errors")
+	assert.Equal(100, groupErr.Total, "total")
+	assert.Equal(map[string]int{"*errors.errorString": 100}, groupErr.TypeCounts)
+	assert.Equal(100, collected, "every error streamed")
+	assert.Equal(100, group.Stats().Failed, "every error counted")
+	assert.Contains(fmt.Sprintf("%+v", err), "100 errors (5 retained):", "format")
+}
+
+func TestGroupErrors_TotalCount_Unset(t *testing.T) {
+	groupErr := pears.GroupErrors{Errs: []error{io.EOF, io.EOF}}
+	assert.Equal(t, 2, groupErr.TotalCount(), "total count")
+	assert.Equal(t, 0, groupErr.Dropped(), "dropped")
+	assert.EqualError(t, groupErr, "2 errors returned. first: EOF")
+}
+
+func TestCollector_Retention_KeepsFirst(t *testing.T) {
+	for _, mode := range []pears.RetentionMode{pears.RetainLast, pears.RetainSample} {
+		collector := pears.NewCollector(pears.CollectorWithRetention(
+			pears.RetentionPolicy{Mode: mode, Max: 1},
+		))
+		collector.Add("root cause", io.ErrUnexpectedEOF)
+		addNumbered(collector, 100)
+
+		err := collector.Err()
+		assert.Equal(t, []string{"root cause"}, retainedNames(t, err), "mode %v", mode)
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF, "mode %v matches first", mode)
+	}
+}
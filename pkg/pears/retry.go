@@ -0,0 +1,86 @@
+package pears
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Backoff configures an exponential delay with jitter.
+type Backoff struct {
+	// Initial is the delay after the first attempt.
+	Initial time.Duration
+	// Max caps the delay. 0 means there is no cap.
+	Max time.Duration
+	// Multiplier is applied to the delay after every attempt. Values below 1 are
+	// treated as 1, resulting in a constant delay.
+	Multiplier float64
+	// Jitter is the fraction of each delay, from 0 to 1, that is randomized. A jitter
+	// of 0.2 will result in delays between 80% and 100% of the calculated delay.
+	Jitter float64
+}
+
+// Delay returns the delay to wait after attempt number attempt, starting at 1.
+func (backoff Backoff) Delay(attempt int) time.Duration {
+	if backoff.Initial <= 0 || attempt < 1 {
+		return 0
+	}
+
+	multiplier := math.Max(backoff.Multiplier, 1)
+	delay := float64(backoff.Initial) * math.Pow(multiplier, float64(attempt-1))
+
+	if backoff.Max > 0 && delay > float64(backoff.Max) {
+		delay = float64(backoff.Max)
+	} else if delay > math.MaxInt64 {
+		delay = math.MaxInt64
+	}
+
+	if backoff.Jitter > 0 {
+		jitter := math.Min(backoff.Jitter, 1)
+		delay -= delay * jitter * rand.Float64()
+	}
+
+	return time.Duration(delay)
+}
+
+// RetryPolicy configures how a Group op is retried when it returns an error.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times an op will be attempted. Values below
+	// 2 result in an op never being retried.
+	MaxAttempts int
+	// Backoff is the delay between attempts.
+	Backoff Backoff
+	// Retryable decides which errors should be retried. If nil, all errors are
+	// retried.
+	Retryable func(err error) bool
+}
+
+// shouldRetry returns true if attempt number attempt, which returned err, should be
+// retried.
+func (policy RetryPolicy) shouldRetry(attempt int, err error) bool {
+	if attempt >= policy.MaxAttempts {
+		return false
+	}
+	return policy.Retryable == nil || policy.Retryable(err)
+}
+
+// sleepCtx sleeps for delay. Returns false if ctx is cancelled before delay elapses.
+func sleepCtx(ctx context.Context, delay time.Duration) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if delay <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,175 @@
+package pears_test
+
+import (
+	"context"
+	"errors"
+	"github.com/peake100/pears-go/pkg/pears"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestBackoff_Delay(t *testing.T) {
+	backoff := pears.Backoff{
+		Initial:    10 * time.Millisecond,
+		Max:        50 * time.Millisecond,
+		Multiplier: 2,
+	}
+
+	assert := assert.New(t)
+	assert.Equal(10*time.Millisecond, backoff.Delay(1), "first delay")
+	assert.Equal(20*time.Millisecond, backoff.Delay(2), "second delay")
+	assert.Equal(40*time.Millisecond, backoff.Delay(3), "third delay")
+	assert.Equal(50*time.Millisecond, backoff.Delay(4), "delay capped")
+	assert.Equal(50*time.Millisecond, backoff.Delay(1000), "delay capped on overflow")
+
+	backoff.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		delay := backoff.Delay(2)
+		assert.GreaterOrEqual(delay, 10*time.Millisecond, "jitter lower bound")
+		assert.LessOrEqual(delay, 20*time.Millisecond, "jitter upper bound")
+	}
+}
+
+func TestRoutineManager_Retry_EventualSuccess(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	manager := pears.NewGroup(ctx, pears.WithRetry(pears.RetryPolicy{MaxAttempts: 3}))
+
+	attempts := 0
+	manager.GoNamed("flaky", func(ctx context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return io.ErrUnexpectedEOF
+		}
+		return nil
+	})
+
+	err := manager.Wait()
+	assert.NoError(t, err, "op succeeded on final attempt")
+	assert.Equal(t, 3, attempts, "op attempted 3 times")
+}
+
+func TestRoutineManager_Retry_AllAttemptsFail(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	manager := pears.NewGroup(ctx)
+
+	attempt := 0
+	attemptErrs := []error{io.ErrUnexpectedEOF, io.ErrClosedPipe, io.EOF}
+	manager.GoNamed(
+		"fails",
+		func(ctx context.Context) error {
+			err := attemptErrs[attempt]
+			attempt++
+			return err
+		},
+		pears.OpWithRetry(pears.RetryPolicy{
+			MaxAttempts: 3,
+			Backoff:     pears.Backoff{Initial: time.Millisecond, Multiplier: 2},
+		}),
+	)
+
+	err := manager.Wait()
+
+	opErr := pears.OpError{}
+	if !assert.ErrorAs(err, &opErr) {
+		t.FailNow()
+	}
+
+	assert.Equal(3, opErr.Attempts, "3 attempts recorded")
+	assert.Equal(attemptErrs, opErr.AttemptErrs(), "all attempt errors recorded")
+	assert.ErrorIs(opErr, io.EOF, "error is final attempt error")
+	assert.EqualError(opErr, "error during 'fails' after 3 attempts: EOF")
+}
+
+func TestRoutineManager_Retry_NotRetryable(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	errPermanent := errors.New("permanent")
+
+	manager := pears.NewGroup(ctx, pears.WithRetry(pears.RetryPolicy{
+		MaxAttempts: 5,
+		Retryable: func(err error) bool {
+			return !errors.Is(err, errPermanent)
+		},
+	}))
+
+	attempts := 0
+	manager.GoNamed("fails", func(ctx context.Context) error {
+		attempts++
+		if attempts == 2 {
+			return errPermanent
+		}
+		return io.ErrUnexpectedEOF
+	})
+
+	err := manager.Wait()
+
+	opErr := pears.OpError{}
+	if !assert.ErrorAs(err, &opErr) {
+		t.FailNow()
+	}
+
+	assert.Equal(2, attempts, "op stopped after non-retryable error")
+	assert.Equal(2, opErr.Attempts, "2 attempts recorded")
+	assert.ErrorIs(opErr, errPermanent, "error is final attempt error")
+}
+
+func TestRoutineManager_Retry_BackoffRespectsCtx(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	manager := pears.NewGroup(ctx)
+
+	firstAttempt := make(chan struct{})
+	attempts := 0
+	manager.GoNamed(
+		"retries",
+		func(ctx context.Context) error {
+			attempts++
+			if attempts == 1 {
+				close(firstAttempt)
+			}
+			return io.ErrUnexpectedEOF
+		},
+		pears.OpWithRetry(pears.RetryPolicy{
+			MaxAttempts: 5,
+			Backoff:     pears.Backoff{Initial: time.Hour},
+		}),
+	)
+
+	// This op will abort the group while the first op is backing off.
+	manager.GoNamed("aborts", func(ctx context.Context) error {
+		<-firstAttempt
+		return io.EOF
+	})
+
+	start := time.Now()
+	err := manager.Wait()
+	assert.Less(time.Since(start), time.Second, "backoff interrupted by abort")
+
+	batchErrs := pears.GroupErrors{}
+	if !assert.ErrorAs(err, &batchErrs) {
+		t.FailNow()
+	}
+	if !assert.Len(batchErrs.Errs, 2, "both ops failed") {
+		t.FailNow()
+	}
+
+	opErr := pears.OpError{}
+	if assert.ErrorAs(batchErrs.Errs[1], &opErr) {
+		assert.Equal("retries", opErr.OpName)
+		assert.Equal(1, opErr.Attempts, "op was not retried after abort")
+	}
+}
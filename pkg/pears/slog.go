@@ -0,0 +1,169 @@
+package pears
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+)
+
+// defaultLogLimit is the number of children GroupErrors.LogValue includes when
+// DisplayLimit is not set.
+const defaultLogLimit = 10
+
+// String implements fmt.Stringer.
+func (mode GroupMatchMode) String() string {
+	switch mode {
+	case GroupMatchNone:
+		return "none"
+	case GroupMatchAny:
+		return "any"
+	case GroupMatchFirst:
+		return "first"
+	case GroupMatchRootCauses:
+		return "root_causes"
+	default:
+		return "GroupMatchMode(" + strconv.Itoa(int(mode)) + ")"
+	}
+}
+
+// LogValue implements slog.LogValuer. The error is logged as a group with "op" and
+// "error" attributes. "attempts", "timed_out" and "fallout" are added when they hold
+// something other than their default value.
+func (err OpError) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("op", err.OpName),
+		slog.Any("error", err.Err),
+	}
+
+	if err.Attempts > 1 {
+		attrs = append(attrs, slog.Int("attempts", err.Attempts))
+	}
+	if err.TimedOut {
+		attrs = append(attrs, slog.Bool("timed_out", true))
+	}
+	if err.Fallout {
+		attrs = append(attrs, slog.Bool("fallout", true))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue implements slog.LogValuer. The error is logged as a group with "count",
+// "match_mode" and "errors" attributes. "errors" is a group keyed by index holding at
+// most DisplayLimit children, or 10 if DisplayLimit is not set. If any children are
+// left out, an "omitted" attribute holds how many.
+//
+// "count" is the TotalCount of collected errors. If some were dropped by a
+// RetentionPolicy, a "retained" attribute holds the number in Errs.
+func (err GroupErrors) LogValue() slog.Value {
+	limit := err.DisplayLimit
+	if limit <= 0 {
+		limit = defaultLogLimit
+	}
+
+	shown := len(err.Errs)
+	if shown > limit {
+		shown = limit
+	}
+
+	children := make([]any, 0, shown)
+	for i, thisErr := range err.Errs[:shown] {
+		children = append(children, slog.Any(strconv.Itoa(i), thisErr))
+	}
+
+	attrs := []slog.Attr{
+		slog.Int("count", err.TotalCount()),
+		slog.String("match_mode", err.MatchMode.String()),
+		slog.Group("errors", children...),
+	}
+	if err.Dropped() > 0 {
+		attrs = append(attrs, slog.Int("retained", len(err.Errs)))
+	}
+	if omitted := len(err.Errs) - shown; omitted > 0 {
+		attrs = append(attrs, slog.Int("omitted", omitted))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue implements slog.LogValuer. The error is logged as a group with "count",
+// "groups" and "buckets" attributes. "buckets" is a group keyed by index holding at
+// most Group.DisplayLimit buckets, or 10 if DisplayLimit is not set. Each bucket logs
+// it's "key", "count" and the names of up to 5 of it's ops under "ops". If any buckets
+// are left out, an "omitted" attribute holds how many.
+func (err AggregatedErrors) LogValue() slog.Value {
+	limit := err.Group.DisplayLimit
+	if limit <= 0 {
+		limit = defaultLogLimit
+	}
+
+	shown := len(err.Buckets)
+	if shown > limit {
+		shown = limit
+	}
+
+	buckets := make([]any, 0, shown)
+	for i, bucket := range err.Buckets[:shown] {
+		names := bucket.OpNames
+		if len(names) > summaryOpNames {
+			names = names[:summaryOpNames]
+		}
+		buckets = append(buckets, slog.Group(
+			strconv.Itoa(i),
+			slog.String("key", fmt.Sprint(bucket.Key)),
+			slog.Int("count", bucket.Count()),
+			slog.Any("ops", names),
+		))
+	}
+
+	attrs := []slog.Attr{
+		slog.Int("count", err.Group.TotalCount()),
+		slog.Int("groups", len(err.Buckets)),
+		slog.Group("buckets", buckets...),
+	}
+	if omitted := len(err.Buckets) - shown; omitted > 0 {
+		attrs = append(attrs, slog.Int("omitted", omitted))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue implements slog.LogValuer. The error is logged as a group with "recovered"
+// and "stack" attributes.
+func (err PanicError) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Any("recovered", err.Recovered),
+		slog.String("stack", err.StackTrace),
+	)
+}
+
+// LogValue implements slog.LogValuer. The error is logged as a group with "error" and
+// "stack" attributes.
+func (err GoexitError) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("error", err.Error()),
+		slog.String("stack", err.StackTrace),
+	)
+}
+
+// LogEach logs every error in err as a separate record on logger, with the error
+// under an "error" attribute and its position under an "index" attribute. If err does
+// not contain a GroupErrors, it is logged as a single record. Nothing is logged if err
+// is nil.
+func LogEach(ctx context.Context, logger *slog.Logger, level slog.Level, msg string, err error) {
+	if err == nil {
+		return
+	}
+
+	groupErr := GroupErrors{}
+	if !errors.As(err, &groupErr) {
+		logger.Log(ctx, level, msg, slog.Any("error", err))
+		return
+	}
+
+	for i, thisErr := range groupErr.Errs {
+		logger.Log(ctx, level, msg, slog.Int("index", i), slog.Any("error", thisErr))
+	}
+}
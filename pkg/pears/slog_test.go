@@ -0,0 +1,137 @@
+package pears_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/peake100/pears-go/pkg/pears"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// logJSON logs err under an "error" attribute with a JSON handler, and returns the
+// decoded "error" value.
+func logJSON(t *testing.T, err error) map[string]interface{} {
+	buffer := new(bytes.Buffer)
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	logger.Error("failed", "error", err)
+
+	record := map[string]interface{}{}
+	if !assert.NoError(t, json.Unmarshal(buffer.Bytes(), &record), "log is JSON") {
+		t.FailNow()
+	}
+
+	value, ok := record["error"].(map[string]interface{})
+	if !assert.True(t, ok, "error logged as group: %v", buffer.String()) {
+		t.FailNow()
+	}
+	return value
+}
+
+func TestOpError_LogValue(t *testing.T) {
+	value := logJSON(t, pears.OpError{
+		OpName:   "read file",
+		Err:      io.EOF,
+		Attempts: 3,
+		Fallout:  true,
+	})
+
+	assert.Equal(t, map[string]interface{}{
+		"op":       "read file",
+		"error":    "EOF",
+		"attempts": float64(3),
+		"fallout":  true,
+	}, value)
+}
+
+func TestGroupErrors_LogValue(t *testing.T) {
+	assert := assert.New(t)
+
+	errs := make([]error, 3)
+	for i := range errs {
+		errs[i] = pears.OpError{OpName: fmt.Sprint("op", i), Err: io.EOF}
+	}
+
+	value := logJSON(t, pears.GroupErrors{
+		MatchMode:    pears.GroupMatchAny,
+		Errs:         errs,
+		DisplayLimit: 2,
+	})
+
+	assert.Equal(float64(3), value["count"], "count")
+	assert.Equal("any", value["match_mode"], "match mode")
+	assert.Equal(float64(1), value["omitted"], "omitted")
+	assert.Equal(map[string]interface{}{
+		"0": map[string]interface{}{"op": "op0", "error": "EOF"},
+		"1": map[string]interface{}{"op": "op1", "error": "EOF"},
+	}, value["errors"], "children")
+}
+
+func TestPanicError_LogValue(t *testing.T) {
+	err := pears.CatchPanic(func() (innerErr error) {
+		panic("boom")
+	})
+
+	value := logJSON(t, err)
+	assert.Equal(t, "boom", value["recovered"], "recovered")
+	assert.Contains(t, value["stack"], "goroutine", "stack")
+}
+
+func TestLogEach(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	logger := slog.New(slog.NewTextHandler(buffer, nil))
+
+	err := fmt.Errorf("wrapped: %w", pears.GroupErrors{
+		Errs: []error{
+			pears.OpError{OpName: "first", Err: io.EOF},
+			pears.OpError{OpName: "second", Err: io.ErrUnexpectedEOF},
+		},
+	})
+
+	pears.LogEach(context.Background(), logger, slog.LevelWarn, "op failed", err)
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if !assert.Len(t, lines, 2, "one record per error") {
+		t.FailNow()
+	}
+	assert.Contains(t, lines[0], "index=0 error.op=first error.error=EOF")
+	assert.Contains(t, lines[1], `index=1 error.op=second error.error="unexpected EOF"`)
+	assert.Contains(t, lines[1], "level=WARN")
+}
+
+func TestGroupMatchMode_String(t *testing.T) {
+	assert.Equal(t, "root_causes", pears.GroupMatchRootCauses.String())
+	assert.Equal(t, "GroupMatchMode(10)", pears.GroupMatchMode(10).String())
+}
+
+func TestAggregatedErrors_LogValue(t *testing.T) {
+	assert := assert.New(t)
+
+	errs := make([]error, 7)
+	for i := range errs {
+		err := io.EOF
+		if i == 6 {
+			err = io.ErrUnexpectedEOF
+		}
+		errs[i] = pears.OpError{OpName: fmt.Sprint("op", i), Err: err}
+	}
+
+	value := logJSON(t, pears.GroupErrors{Errs: errs, DisplayLimit: 1}.Summarize(nil))
+
+	assert.Equal(float64(7), value["count"], "count")
+	assert.Equal(float64(2), value["groups"], "groups")
+	assert.Equal(float64(1), value["omitted"], "omitted")
+	assert.Equal(map[string]interface{}{
+		"0": map[string]interface{}{
+			"key":   "EOF",
+			"count": float64(6),
+			"ops": []interface{}{
+				"op0", "op1", "op2", "op3", "op4",
+			},
+		},
+	}, value["buckets"], "buckets")
+}
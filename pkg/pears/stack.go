@@ -0,0 +1,117 @@
+package pears
+
+import (
+	"runtime"
+	"strings"
+)
+
+// pearsPackage is the import path of this package, used to identify it's frames.
+const pearsPackage = "github.com/peake100/pears-go/pkg/pears"
+
+// StackFrame is a single parsed frame of a stack trace.
+type StackFrame struct {
+	// Function is the fully qualified name of the function, like
+	// "github.com/peake100/pears-go/pkg/pears.CatchPanic.func1".
+	Function string `json:"function"`
+	// File is the full path of the source file.
+	File string `json:"file"`
+	// Line is the line number in File.
+	Line int `json:"line"`
+	// Package is the import path of the package Function belongs to, like
+	// "github.com/peake100/pears-go/pkg/pears".
+	Package string `json:"package"`
+}
+
+// IsRuntime returns true if the frame belongs to the runtime package or one of it's
+// sub-packages.
+func (frame StackFrame) IsRuntime() bool {
+	return frame.Package == "runtime" || strings.HasPrefix(frame.Package, "runtime/")
+}
+
+// IsPears returns true if the frame belongs to this package. Frames from other
+// packages in this module, or from this package's tests, return false.
+func (frame StackFrame) IsPears() bool {
+	return frame.Package == pearsPackage
+}
+
+// funcPackage returns the import path of the package a fully qualified function name
+// belongs to.
+func funcPackage(function string) string {
+	// The package name ends at the first '.' after the last '/' of the import path.
+	lastSlash := strings.LastIndex(function, "/")
+	dot := strings.Index(function[lastSlash+1:], ".")
+	if dot < 0 {
+		return function
+	}
+	return function[:lastSlash+1+dot]
+}
+
+// captureFrames returns the frames of the calling goroutine's stack. skip is the number
+// of frames to skip, with 0 identifying the caller of captureFrames.
+func captureFrames(skip int) []StackFrame {
+	pcs := make([]uintptr, 64)
+	for {
+		// Skip runtime.Callers and captureFrames itself.
+		count := runtime.Callers(skip+2, pcs)
+		if count < len(pcs) {
+			pcs = pcs[:count]
+			break
+		}
+		pcs = make([]uintptr, len(pcs)*2)
+	}
+
+	frames := make([]StackFrame, 0, len(pcs))
+	callers := runtime.CallersFrames(pcs)
+	for {
+		frame, more := callers.Next()
+		frames = append(frames, StackFrame{
+			Function: frame.Function,
+			File:     frame.File,
+			Line:     frame.Line,
+			Package:  funcPackage(frame.Function),
+		})
+		if !more {
+			break
+		}
+	}
+
+	return frames
+}
+
+// TrimmedFrames returns Frames without any frames from the runtime or from this
+// package, leaving only the caller's code.
+func (err PanicError) TrimmedFrames() []StackFrame {
+	return trimFrames(err.Frames)
+}
+
+// trimFrames returns frames without any frames from the runtime or from this package.
+func trimFrames(frames []StackFrame) []StackFrame {
+	trimmed := make([]StackFrame, 0, len(frames))
+	for _, frame := range frames {
+		if frame.IsRuntime() || frame.IsPears() {
+			continue
+		}
+		trimmed = append(trimmed, frame)
+	}
+	return trimmed
+}
+
+// PanicFrame returns the frame the panic originated from: the first frame after
+// runtime.gopanic that does not belong to the runtime or to this package. Returns false
+// if no such frame exists.
+func (err PanicError) PanicFrame() (StackFrame, bool) {
+	frames := err.Frames
+	for i, frame := range frames {
+		if frame.Function == "runtime.gopanic" {
+			frames = frames[i+1:]
+			break
+		}
+	}
+
+	for _, frame := range frames {
+		if !frame.IsRuntime() && !frame.IsPears() {
+			return frame, true
+		}
+	}
+	return StackFrame{}, false
+}
@@ -0,0 +1,121 @@
+package pears_test
+
+import (
+	"github.com/peake100/pears-go/pkg/pears"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+const testPackage = "github.com/peake100/pears-go/pkg/pears_test"
+
+// panicsHere panics with a string value.
+func panicsHere() error {
+	panic("boom")
+}
+
+// dereferencesNil panics with a runtime error.
+func dereferencesNil() error {
+	var value *int
+	return assertNotReached(*value)
+}
+
+// assertNotReached exists so dereferencesNil has something to do with it's value.
+func assertNotReached(int) error {
+	return nil
+}
+
+// catchPanicErr runs mayPanic through CatchPanic and fails the test if a PanicError is
+// not returned.
+func catchPanicErr(t *testing.T, mayPanic func() error) pears.PanicError {
+	panicErr := pears.PanicError{}
+	if !assert.ErrorAs(t, pears.CatchPanic(mayPanic), &panicErr, "panic error") {
+		t.FailNow()
+	}
+	return panicErr
+}
+
+func TestPanicError_Frames(t *testing.T) {
+	assert := assert.New(t)
+
+	panicErr := catchPanicErr(t, panicsHere)
+
+	if !assert.NotEmpty(panicErr.Frames, "frames") {
+		t.FailNow()
+	}
+	assert.Equal("runtime.gopanic", panicErr.Frames[0].Function, "first frame")
+	assert.Equal("runtime", panicErr.Frames[0].Package, "first frame package")
+	assert.True(panicErr.Frames[0].IsRuntime(), "first frame is runtime")
+
+	foundPears := false
+	for _, frame := range panicErr.Frames {
+		if frame.IsPears() {
+			foundPears = true
+			assert.Equal(
+				"github.com/peake100/pears-go/pkg/pears", frame.Package, "pears package",
+			)
+		}
+	}
+	assert.True(foundPears, "pears frames included")
+}
+
+func TestPanicError_TrimmedFrames(t *testing.T) {
+	assert := assert.New(t)
+
+	panicErr := catchPanicErr(t, panicsHere)
+	trimmed := panicErr.TrimmedFrames()
+
+	if !assert.NotEmpty(trimmed, "trimmed frames") {
+		t.FailNow()
+	}
+	for _, frame := range trimmed {
+		assert.False(frame.IsRuntime(), "runtime frame: %v", frame.Function)
+		assert.False(frame.IsPears(), "pears frame: %v", frame.Function)
+	}
+
+	assert.Equal(testPackage+".panicsHere", trimmed[0].Function, "first frame")
+	assert.Equal(testPackage, trimmed[0].Package, "test frames kept")
+	assert.True(strings.HasSuffix(trimmed[0].File, "stack_test.go"), "file")
+}
+
+func TestPanicError_PanicFrame(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		MayPanic func() error
+		Function string
+		Line     int
+	}{
+		{
+			Name:     "Panic",
+			MayPanic: panicsHere,
+			Function: testPackage + ".panicsHere",
+			Line:     14,
+		},
+		{
+			Name:     "NilDereference",
+			MayPanic: dereferencesNil,
+			Function: testPackage + ".dereferencesNil",
+			Line:     20,
+		},
+	}
+
+	for _, thisCase := range testCases {
+		t.Run(thisCase.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			frame, ok := catchPanicErr(t, thisCase.MayPanic).PanicFrame()
+			if !assert.True(ok, "frame found") {
+				t.FailNow()
+			}
+
+			assert.Equal(thisCase.Function, frame.Function, "function")
+			assert.Equal(thisCase.Line, frame.Line, "line")
+			assert.Equal(testPackage, frame.Package, "package")
+		})
+	}
+}
+
+func TestPanicError_PanicFrame_NoFrames(t *testing.T) {
+	_, ok := pears.PanicError{}.PanicFrame()
+	assert.False(t, ok, "no frame found")
+}
@@ -0,0 +1,97 @@
+package pears
+
+import (
+	"sort"
+	"sync/atomic"
+)
+
+// GroupStats holds counts of a Group's ops at a single point in time.
+type GroupStats struct {
+	// Launched is the number of ops launched by Go, GoNamed or SubGroup. It may
+	// briefly exceed the sum of the other counts while an op is being started.
+	Launched int
+	// Queued is the number of ops waiting for a slot when WithMaxConcurrency is set.
+	Queued int
+	// Running is the number of ops that have started but not yet returned, including
+	// sub-groups that have not been waited on.
+	Running int
+	// Succeeded is the number of ops that returned without an error.
+	Succeeded int
+	// Failed is the number of ops that returned an error, including queued ops that
+	// were never started.
+	Failed int
+}
+
+// GroupSnapshot is a consistent view of a Group's ops at a single point in time.
+type GroupSnapshot struct {
+	// Stats holds the counts of the Group's ops.
+	Stats GroupStats
+	// Running describes every op that has started but not yet returned, in launch
+	// order.
+	Running []OpInfo
+	// Queued describes every op waiting for a slot, in the order they will be started.
+	Queued []OpInfo
+}
+
+// Snapshot returns the current state of runner's ops. It is safe to call at any time
+// from any routine, including while ops are being launched and collected.
+func (runner *Group) Snapshot() GroupSnapshot {
+	runner.queueLock.Lock()
+	defer runner.queueLock.Unlock()
+
+	queued := make([]OpInfo, len(runner.queue))
+	for i, op := range runner.queue {
+		queued[i] = op.info()
+	}
+
+	return GroupSnapshot{
+		Stats:   runner.statsLocked(),
+		Running: runner.runningLocked(),
+		Queued:  queued,
+	}
+}
+
+// Stats returns the current counts of runner's ops. It is safe to call at any time
+// from any routine.
+func (runner *Group) Stats() GroupStats {
+	runner.queueLock.Lock()
+	defer runner.queueLock.Unlock()
+
+	return runner.statsLocked()
+}
+
+// Running returns the name, launch index and start time of every op that has started
+// but not yet returned, in launch order. It is safe to call at any time from any
+// routine.
+func (runner *Group) Running() []OpInfo {
+	runner.queueLock.Lock()
+	defer runner.queueLock.Unlock()
+
+	return runner.runningLocked()
+}
+
+// statsLocked builds our GroupStats. queueLock must be held.
+func (runner *Group) statsLocked() GroupStats {
+	finished := int(atomic.LoadInt64(&runner.finished))
+	return GroupStats{
+		Launched:  int(atomic.LoadInt64(&runner.launched)),
+		Queued:    len(runner.queue),
+		Running:   len(runner.inFlight),
+		Succeeded: finished - runner.failed,
+		Failed:    runner.failed,
+	}
+}
+
+// runningLocked returns the OpInfo of every in-flight op in launch order. queueLock
+// must be held.
+func (runner *Group) runningLocked() []OpInfo {
+	running := make([]OpInfo, 0, len(runner.inFlight))
+	for op := range runner.inFlight {
+		running = append(running, op.info())
+	}
+
+	sort.Slice(running, func(i, j int) bool {
+		return running[i].LaunchIndex < running[j].LaunchIndex
+	})
+	return running
+}
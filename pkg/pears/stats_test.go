@@ -0,0 +1,134 @@
+package pears_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/peake100/pears-go/pkg/pears"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestGroup_Snapshot(t *testing.T) {
+	assert := assert.New(t)
+
+	release := make(chan struct{})
+	started := make(chan struct{}, 4)
+
+	group := pears.NewGroup(
+		context.Background(),
+		pears.WithMaxConcurrency(2),
+		pears.WithAbortOnError(false),
+	)
+
+	for i := 0; i < 4; i++ {
+		i := i
+		group.GoNamed(fmt.Sprint("op ", i), func(ctx context.Context) error {
+			started <- struct{}{}
+			<-release
+			if i == 3 {
+				return io.EOF
+			}
+			return nil
+		})
+	}
+
+	// Wait for the first two ops to start.
+	<-started
+	<-started
+
+	before := time.Now()
+	snapshot := group.Snapshot()
+	assert.Equal(pears.GroupStats{
+		Launched:  4,
+		Queued:    2,
+		Running:   2,
+		Succeeded: 0,
+		Failed:    0,
+	}, snapshot.Stats, "stats")
+
+	if assert.Len(snapshot.Running, 2, "running ops") {
+		assert.Equal("op 0", snapshot.Running[0].Name, "first running op")
+		assert.Equal("op 1", snapshot.Running[1].Name, "second running op")
+		assert.False(snapshot.Running[0].Started.IsZero(), "start time set")
+		assert.False(snapshot.Running[0].Started.After(before), "start time")
+	}
+	if assert.Len(snapshot.Queued, 2, "queued ops") {
+		assert.Equal("op 2", snapshot.Queued[0].Name, "first queued op")
+		assert.Equal(3, snapshot.Queued[1].LaunchIndex, "second queued op")
+	}
+
+	assert.Equal(snapshot.Running, group.Running(), "Running matches snapshot")
+	assert.Equal(snapshot.Stats, group.Stats(), "Stats matches snapshot")
+
+	close(release)
+	assert.ErrorIs(group.Wait(), io.EOF, "op error returned")
+
+	assert.Equal(pears.GroupStats{
+		Launched:  4,
+		Queued:    0,
+		Running:   0,
+		Succeeded: 3,
+		Failed:    1,
+	}, group.Stats(), "final stats")
+	assert.Empty(group.Running(), "no running ops")
+}
+
+func TestGroup_Snapshot_SubGroup(t *testing.T) {
+	assert := assert.New(t)
+
+	group := pears.NewGroup(context.Background())
+	child := group.SubGroup("child")
+
+	running := group.Running()
+	if assert.Len(running, 1, "sub-group running") {
+		assert.Equal("child", running[0].Name, "sub-group name")
+	}
+
+	assert.NoError(child.Wait(), "child error")
+	assert.NoError(group.Wait(), "group error")
+	assert.Equal(1, group.Stats().Succeeded, "sub-group succeeded")
+}
+
+func TestGroup_Snapshot_Concurrent(t *testing.T) {
+	group := pears.NewGroup(
+		context.Background(),
+		pears.WithMaxConcurrency(5),
+		pears.WithAbortOnError(false),
+	)
+
+	stop := make(chan struct{})
+	inspected := make(chan struct{})
+	go func() {
+		defer close(inspected)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			_ = group.Snapshot()
+			_ = group.Stats()
+			_ = group.Running()
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		i := i
+		group.Go(func(ctx context.Context) error {
+			if i%2 == 0 {
+				return io.EOF
+			}
+			return nil
+		})
+	}
+
+	_ = group.Wait()
+	close(stop)
+	<-inspected
+
+	stats := group.Stats()
+	assert.Equal(t, 50, stats.Failed, "failed")
+	assert.Equal(t, 50, stats.Succeeded, "succeeded")
+}
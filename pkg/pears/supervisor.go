@@ -0,0 +1,390 @@
+package pears
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// RestartStrategy determines which children a Supervisor restarts when one of them
+// fails.
+type RestartStrategy int
+
+const (
+	// RestartOneForOne restarts only the child that failed.
+	RestartOneForOne RestartStrategy = iota
+	// RestartOneForAll stops every other running child when one fails, then restarts
+	// all of them.
+	RestartOneForAll
+	// RestartRestForOne stops every running child added after the one that failed,
+	// then restarts the failed child and those stopped children in the order they were
+	// added.
+	RestartRestForOne
+)
+
+// Supervisor runs long-lived child workers and restarts them when they fail, in the
+// style of an Erlang supervisor.
+//
+// A child is restarted when it returns an error or panics. A child that returns nil
+// has completed, and is not restarted unless the RestartStrategy restarts it alongside
+// a failed sibling. Every child is run with CatchPanic.
+//
+// Each child has a restart budget set by SupervisorWithIntensity. When a child fails
+// more often than it's budget allows, the Supervisor stops all children and Run returns
+// a GroupErrors holding every failure of that child within the intensity window.
+//
+// Supervisor must be created with a constructor function: NewSupervisor.
+type Supervisor struct {
+	// ctx is the context passed to all children. It is cancelled when Run returns.
+	ctx context.Context
+	// cancel cancels ctx.
+	cancel context.CancelFunc
+
+	// running will be atomically inspected by Add and Run to see if Run has already
+	// been called.
+	//
+	// 1 = Run has been called.
+	running int32
+
+	// children are all children added to the supervisor, in the order they were added.
+	children []*supervisedChild
+	// exits receives a childExit every time a child returns.
+	exits chan childExit
+
+	// SETTINGS --------
+
+	// strategy is the RestartStrategy used when a child fails.
+	strategy RestartStrategy
+	// maxRestarts is the number of times a single child may be restarted within
+	// restartWindow.
+	maxRestarts int
+	// restartWindow is the window of time maxRestarts applies to.
+	restartWindow time.Duration
+	// backoff is the delay before restarting failed children.
+	backoff Backoff
+	// errMode is the GroupMatchMode of the GroupErrors returned by Run.
+	errMode GroupMatchMode
+}
+
+// supervisedChild tracks a single child of a Supervisor. It is only accessed from the
+// routine running Supervisor.Run.
+type supervisedChild struct {
+	// name is the name of the child.
+	name string
+	// run is the child's worker function.
+	run func(ctx context.Context) error
+	// index is the order the child was added in.
+	index int
+
+	// isRunning is true while the child's current run has not returned.
+	isRunning bool
+	// runs is the number of times the child has been started.
+	runs int
+	// cancel cancels the context of the current run.
+	cancel context.CancelFunc
+	// done is closed when the current run returns.
+	done chan struct{}
+
+	// failures holds an OpError for every failure within the restart window.
+	failures []error
+}
+
+// childExit reports that a single run of a child returned.
+type childExit struct {
+	// child is the child that returned.
+	child *supervisedChild
+	// run is the run number that returned.
+	run int
+	// err is the error the run returned.
+	err error
+	// started is when the run started.
+	started time.Time
+	// ended is when the run returned.
+	ended time.Time
+}
+
+// Add adds a child worker named name. Children are started in the order they are
+// added when Run is called.
+//
+// Add will panic if called after Run.
+func (sup *Supervisor) Add(name string, run func(ctx context.Context) error) {
+	if atomic.LoadInt32(&sup.running) != 0 {
+		panic("Supervisor.Add called after Supervisor.Run")
+	}
+
+	sup.children = append(sup.children, &supervisedChild{
+		name:  name,
+		run:   run,
+		index: len(sup.children),
+	})
+}
+
+// Run starts all children and supervises them until the Supervisor's context is
+// cancelled, every child completes, or a child exceeds it's restart budget.
+//
+// Returns nil if the context was cancelled or every child completed. Otherwise returns
+// a GroupErrors holding the failures of the child that exceeded it's budget. All
+// children are stopped before Run returns.
+//
+// Run will panic if called multiple times. Supervisor cannot be reused.
+func (sup *Supervisor) Run() error {
+	if !atomic.CompareAndSwapInt32(&sup.running, 0, 1) {
+		panic("Supervisor.Run called multiple times")
+	}
+	defer sup.cancel()
+
+	// Every run sends exactly one exit. At most one run of each child is live, and at
+	// most one stale exit per child can be waiting when a new generation starts.
+	sup.exits = make(chan childExit, 2*len(sup.children))
+
+	for _, child := range sup.children {
+		sup.start(child)
+	}
+
+	for sup.anyRunning() {
+		select {
+		case <-sup.ctx.Done():
+			sup.stopAll()
+			return nil
+		case exit := <-sup.exits:
+			if err := sup.handleExit(exit); err != nil {
+				sup.stopAll()
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// handleExit processes a single childExit, restarting children if needed. Returns a
+// non-nil error if the child has exceeded it's restart budget.
+func (sup *Supervisor) handleExit(exit childExit) error {
+	child := exit.child
+
+	// Ignore exits from runs we stopped ourselves, which have already been handled.
+	if exit.run != child.runs || !child.isRunning {
+		return nil
+	}
+	child.isRunning = false
+
+	if exit.err == nil || sup.ctx.Err() != nil {
+		return nil
+	}
+
+	child.recordFailure(exit, sup.restartWindow)
+	if len(child.failures) > sup.maxRestarts {
+		return GroupErrors{
+			MatchMode: sup.errMode,
+			Errs:      child.failures,
+		}
+	}
+
+	restart := sup.restartSet(child)
+	for _, sibling := range restart {
+		sup.stop(sibling)
+	}
+
+	// If we are cancelled during our backoff, Run will stop on it's next loop.
+	if !sleepCtx(sup.ctx, sup.backoff.Delay(len(child.failures))) {
+		return nil
+	}
+
+	sup.start(child)
+	for _, sibling := range restart {
+		sup.start(sibling)
+	}
+
+	return nil
+}
+
+// recordFailure adds exit to the child's failures and drops any failures older than
+// window.
+func (child *supervisedChild) recordFailure(exit childExit, window time.Duration) {
+	windowStart := exit.ended.Add(-window)
+
+	kept := child.failures[:0]
+	for _, failure := range child.failures {
+		if failure.(OpError).Ended.After(windowStart) {
+			kept = append(kept, failure)
+		}
+	}
+
+	child.failures = append(kept, OpError{
+		OpName:          child.name,
+		Err:             exit.err,
+		Attempts:        exit.run,
+		attemptErrs:     &[]error{exit.err},
+		Started:         exit.started,
+		Ended:           exit.ended,
+		LaunchIndex:     child.index,
+		CollectionIndex: len(kept),
+	})
+}
+
+// restartSet returns the running siblings of failed that must be stopped and restarted
+// alongside it under our RestartStrategy.
+func (sup *Supervisor) restartSet(failed *supervisedChild) []*supervisedChild {
+	var candidates []*supervisedChild
+	switch sup.strategy {
+	case RestartOneForAll:
+		candidates = sup.children
+	case RestartRestForOne:
+		candidates = sup.children[failed.index+1:]
+	default:
+		return nil
+	}
+
+	var restart []*supervisedChild
+	for _, sibling := range candidates {
+		if sibling != failed && sibling.isRunning {
+			restart = append(restart, sibling)
+		}
+	}
+	return restart
+}
+
+// start launches a new run of child.
+func (sup *Supervisor) start(child *supervisedChild) {
+	ctx, cancel := context.WithCancel(sup.ctx)
+
+	child.runs++
+	child.isRunning = true
+	child.cancel = cancel
+	child.done = make(chan struct{})
+
+	run, done := child.runs, child.done
+	go func() {
+		defer close(done)
+		defer cancel()
+
+		started := time.Now()
+		err := CatchPanic(func() (innerErr error) {
+			return child.run(ctx)
+		})
+
+		sup.exits <- childExit{
+			child:   child,
+			run:     run,
+			err:     err,
+			started: started,
+			ended:   time.Now(),
+		}
+	}()
+}
+
+// stop cancels child's current run and waits for it to return.
+func (sup *Supervisor) stop(child *supervisedChild) {
+	if !child.isRunning {
+		return
+	}
+	child.cancel()
+	<-child.done
+	child.isRunning = false
+}
+
+// stopAll stops every running child.
+func (sup *Supervisor) stopAll() {
+	for _, child := range sup.children {
+		sup.stop(child)
+	}
+}
+
+// anyRunning returns true if any child is running.
+func (sup *Supervisor) anyRunning() bool {
+	for _, child := range sup.children {
+		if child.isRunning {
+			return true
+		}
+	}
+	return false
+}
+
+// NewSupervisor creates a new *Supervisor for running long-lived workers.
+//
+// ctx is the main context.Context for the supervisor. It will be used as the parent
+// context of the ctx parameter passed to all children. Cancelling it stops all children
+// and causes Run to return.
+//
+// The returned supervisor can be configured with opts.
+//
+// Options and defaults:
+//
+// - SupervisorWithStrategy: RestartOneForOne
+//
+// - SupervisorWithIntensity: 3 restarts in 5 seconds
+//
+// - SupervisorWithBackoff: Backoff{Initial: 100ms, Max: 5s, Multiplier: 2, Jitter: 0.2}
+//
+// - SupervisorWithErrMode: GroupMatchFirst
+func NewSupervisor(ctx context.Context, opts ...SupervisorOption) *Supervisor {
+	supervisorCtx, cancel := context.WithCancel(ctx)
+
+	sup := &Supervisor{
+		ctx:           supervisorCtx,
+		cancel:        cancel,
+		running:       0,
+		children:      nil,
+		exits:         nil,
+		strategy:      RestartOneForOne,
+		maxRestarts:   3,
+		restartWindow: 5 * time.Second,
+		backoff: Backoff{
+			Initial:    100 * time.Millisecond,
+			Max:        5 * time.Second,
+			Multiplier: 2,
+			Jitter:     0.2,
+		},
+		errMode: GroupMatchFirst,
+	}
+
+	for _, opt := range opts {
+		opt(sup)
+	}
+
+	return sup
+}
+
+// SupervisorOption defines an option for Supervisor.
+type SupervisorOption = func(sup *Supervisor)
+
+// SupervisorWithStrategy sets the RestartStrategy used when a child fails.
+//
+// Default: RestartOneForOne.
+func SupervisorWithStrategy(strategy RestartStrategy) SupervisorOption {
+	return func(sup *Supervisor) {
+		sup.strategy = strategy
+	}
+}
+
+// SupervisorWithIntensity sets the restart budget of each child: a child may be
+// restarted at most maxRestarts times within any window of time. A child that fails
+// again after using up it's budget causes the Supervisor to fail.
+//
+// Default: 3 restarts in 5 seconds.
+func SupervisorWithIntensity(maxRestarts int, window time.Duration) SupervisorOption {
+	return func(sup *Supervisor) {
+		sup.maxRestarts = maxRestarts
+		sup.restartWindow = window
+	}
+}
+
+// SupervisorWithBackoff sets the delay before restarting failed children. The delay
+// grows with the number of times the failed child has failed within the restart
+// window.
+//
+// Default: Backoff{Initial: 100ms, Max: 5s, Multiplier: 2, Jitter: 0.2}.
+func SupervisorWithBackoff(backoff Backoff) SupervisorOption {
+	return func(sup *Supervisor) {
+		sup.backoff = backoff
+	}
+}
+
+// SupervisorWithErrMode sets the GroupMatchMode of the GroupErrors returned by Run.
+//
+// Default: GroupMatchFirst.
+func SupervisorWithErrMode(mode GroupMatchMode) SupervisorOption {
+	return func(sup *Supervisor) {
+		sup.errMode = mode
+	}
+}
@@ -0,0 +1,204 @@
+package pears_test
+
+import (
+	"context"
+	"github.com/peake100/pears-go/pkg/pears"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+// startCounter counts how many times each supervised child has been started.
+type startCounter struct {
+	lock   sync.Mutex
+	counts map[string]int
+}
+
+// start records a start of name and returns how many times it has been started.
+func (counter *startCounter) start(name string) int {
+	counter.lock.Lock()
+	defer counter.lock.Unlock()
+	if counter.counts == nil {
+		counter.counts = make(map[string]int)
+	}
+	counter.counts[name]++
+	return counter.counts[name]
+}
+
+// get returns how many times name has been started.
+func (counter *startCounter) get(name string) int {
+	counter.lock.Lock()
+	defer counter.lock.Unlock()
+	return counter.counts[name]
+}
+
+// blockUntilDone blocks until ctx is cancelled.
+func blockUntilDone(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestSupervisor_Strategies(t *testing.T) {
+	testCases := []struct {
+		// The name of the test case.
+		Name string
+		// The restart strategy to test.
+		Strategy pears.RestartStrategy
+		// The expected number of starts for children "a", "b" and "c" when "b" fails
+		// once.
+		ExpectedStarts map[string]int
+	}{
+		{
+			Name:           "OneForOne",
+			Strategy:       pears.RestartOneForOne,
+			ExpectedStarts: map[string]int{"a": 1, "b": 2, "c": 1},
+		},
+		{
+			Name:           "OneForAll",
+			Strategy:       pears.RestartOneForAll,
+			ExpectedStarts: map[string]int{"a": 2, "b": 2, "c": 2},
+		},
+		{
+			Name:           "RestForOne",
+			Strategy:       pears.RestartRestForOne,
+			ExpectedStarts: map[string]int{"a": 1, "b": 2, "c": 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			supervisor := pears.NewSupervisor(
+				ctx,
+				pears.SupervisorWithStrategy(tc.Strategy),
+				pears.SupervisorWithBackoff(pears.Backoff{}),
+			)
+
+			counter := new(startCounter)
+
+			// Each expected start marks this as done, so we can stop the supervisor
+			// once every child has been restarted.
+			allStarted := new(sync.WaitGroup)
+			for _, expected := range tc.ExpectedStarts {
+				allStarted.Add(expected)
+			}
+
+			for _, name := range []string{"a", "b", "c"} {
+				childName := name
+				supervisor.Add(childName, func(ctx context.Context) error {
+					starts := counter.start(childName)
+					if starts <= tc.ExpectedStarts[childName] {
+						allStarted.Done()
+					}
+
+					if childName == "b" && starts == 1 {
+						return io.EOF
+					}
+					return blockUntilDone(ctx)
+				})
+			}
+
+			go func() {
+				allStarted.Wait()
+				cancel()
+			}()
+
+			err := supervisor.Run()
+			assert.NoError(t, err, "supervisor stopped by context")
+
+			for name, expected := range tc.ExpectedStarts {
+				assert.Equal(t, expected, counter.get(name), "starts of %v", name)
+			}
+		})
+	}
+}
+
+func TestSupervisor_RestartBudgetExceeded(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	supervisor := pears.NewSupervisor(
+		ctx,
+		pears.SupervisorWithIntensity(2, time.Minute),
+		pears.SupervisorWithBackoff(pears.Backoff{Initial: time.Millisecond}),
+	)
+
+	stableStopped := make(chan struct{})
+	supervisor.Add("stable", func(ctx context.Context) error {
+		defer close(stableStopped)
+		return blockUntilDone(ctx)
+	})
+
+	supervisor.Add("bad", func(ctx context.Context) error {
+		panic(io.EOF)
+	})
+
+	err := supervisor.Run()
+
+	select {
+	case <-stableStopped:
+	default:
+		t.Error("stable child was not stopped")
+	}
+
+	batchErrs := pears.GroupErrors{}
+	if !assert.ErrorAs(err, &batchErrs) {
+		t.FailNow()
+	}
+	if !assert.Len(batchErrs.Errs, 3, "initial run and 2 restarts failed") {
+		t.FailNow()
+	}
+
+	for i, thisErr := range batchErrs.Errs {
+		opErr := pears.OpError{}
+		if !assert.ErrorAs(thisErr, &opErr) {
+			continue
+		}
+		assert.Equal("bad", opErr.OpName, "failure from bad child")
+		assert.Equal(i+1, opErr.Attempts, "run number recorded")
+
+		panicErr := pears.PanicError{}
+		assert.ErrorAs(opErr, &panicErr, "panic recovered")
+		assert.ErrorIs(opErr, io.EOF, "error is io.EOF")
+	}
+}
+
+func TestSupervisor_AllChildrenComplete(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	supervisor := pears.NewSupervisor(ctx)
+
+	counter := new(startCounter)
+	for _, name := range []string{"a", "b"} {
+		childName := name
+		supervisor.Add(childName, func(ctx context.Context) error {
+			counter.start(childName)
+			return nil
+		})
+	}
+
+	err := supervisor.Run()
+	assert.NoError(t, err, "supervisor returns once all children complete")
+	assert.Equal(t, 1, counter.get("a"), "completed child not restarted")
+	assert.Equal(t, 1, counter.get("b"), "completed child not restarted")
+}
+
+func TestSupervisor_PanicsOnMisuse(t *testing.T) {
+	supervisor := pears.NewSupervisor(context.Background())
+	supervisor.Run()
+
+	assert.Panics(t, func() {
+		supervisor.Run()
+	}, "panic on second call to Run()")
+
+	assert.Panics(t, func() {
+		supervisor.Add("late", blockUntilDone)
+	}, "panic on Add() after Run()")
+}